@@ -0,0 +1,120 @@
+// Package async implements tracing of goroutines. Goroutines, which may
+// outlive the request spawning them, get spans following from span of
+// request and context detached from its cancellation. Goroutines joined
+// with Group get child spans of spawning span.
+package async
+
+import (
+	"context"
+	"time"
+
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"golang.org/x/sync/errgroup"
+)
+
+// component is component tag of goroutine spans
+const component = "async"
+
+// Go runs fn in new goroutine, which is not waited by caller. Span of
+// goroutine follows from span in given context and context given to fn
+// has values of ctx, but it is not cancelled with ctx. Error returned
+// by fn is tagged to span.
+func Go(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...Option) {
+	cfg := newConfig(opts...)
+	sopts := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		cfg.tags,
+	}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		sopts = append(sopts, opentracing.FollowsFrom(parent.Context()))
+	}
+	span := opentracing.StartSpan(name, sopts...)
+	utils.PromoteContextBaggage(ctx, span)
+	ctx = opentracing.ContextWithSpan(Detach(ctx), span)
+
+	go func() {
+		defer span.Finish()
+		setError(span, fn(ctx))
+	}()
+}
+
+// Detach returns context, which has values of ctx including its span,
+// but which has no deadline and is not cancelled with ctx
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+// detachedContext passes values of parent context and ignores its cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// Group is errgroup.Group, which traces its goroutines. Goroutines are
+// waited by Wait, so their spans are children of span in context.
+type Group struct {
+	group *errgroup.Group
+	ctx   context.Context
+	cfg   *config
+}
+
+// WithContext returns new group and context derived from ctx like
+// errgroup.WithContext. Context is cancelled, when goroutine returns
+// error or Wait returns.
+func WithContext(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	group, ctx := errgroup.WithContext(ctx)
+	return &Group{group: group, ctx: ctx, cfg: newConfig(opts...)}, ctx
+}
+
+// Go runs fn in new goroutine with context of group containing child span
+// of span in context of group. Error returned by fn is tagged to span
+// and first error is returned by Wait.
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	sopts := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		g.cfg.tags,
+	}
+	if parent := opentracing.SpanFromContext(g.ctx); parent != nil {
+		sopts = append(sopts, opentracing.ChildOf(parent.Context()))
+	}
+	span := opentracing.StartSpan(name, sopts...)
+	utils.PromoteContextBaggage(g.ctx, span)
+	ctx := opentracing.ContextWithSpan(g.ctx, span)
+
+	g.group.Go(func() error {
+		defer span.Finish()
+		err := fn(ctx)
+		setError(span, err)
+		return err
+	})
+}
+
+// Wait waits all goroutines of group and returns first error returned by them
+func (g *Group) Wait() error {
+	return g.group.Wait()
+}
+
+// setError tags error to span
+func setError(span opentracing.Span, err error) {
+	if err != nil {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+}
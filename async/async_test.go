@@ -0,0 +1,120 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer/fakeagent"
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+func TestGo(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "POST /orders")
+	ctx, cancel := context.WithCancel(ctx)
+	finished := make(chan error)
+	Go(ctx, "send.receipt", func(ctx context.Context) error {
+		<-finished
+		if ctx.Err() != nil {
+			t.Errorf("goroutine context cancelled with request: %v", ctx.Err())
+		}
+		return errors.New("mail server down")
+	}, WithTags(opentracing.Tags{"order_id": 42}))
+	cancel()
+	root.Finish()
+	close(finished)
+
+	var span *mocktracer.MockSpan
+	for i := 0; i < 100 && span == nil; i++ {
+		time.Sleep(time.Millisecond)
+		span = recorder.FindSpan("send.receipt")
+	}
+	if span == nil {
+		t.Fatalf("goroutine span not finished")
+	}
+	parent := recorder.RequireSpan(t, "POST /orders")
+	if span.ParentID != parent.SpanContext.SpanID || span.SpanContext.TraceID != parent.SpanContext.TraceID {
+		t.Errorf("goroutine span not in request trace")
+	}
+	tracetest.AssertTags(t, span, map[string]interface{}{"component": "async", "order_id": 42, "server.errors": "mail server down"})
+	tracetest.AssertError(t, span, true)
+}
+
+func TestDetach(t *testing.T) {
+	type key struct{}
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), key{}, "v"), time.Millisecond)
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil || detached.Done() != nil {
+		t.Errorf("detached context cancelled")
+	}
+	if _, ok := detached.Deadline(); ok {
+		t.Errorf("detached context has deadline")
+	}
+	if detached.Value(key{}) != "v" {
+		t.Errorf("value not passed to detached context")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "GET /dashboard")
+	g, ctx := WithContext(ctx)
+	g.Go("fetch.orders", func(ctx context.Context) error {
+		if opentracing.SpanFromContext(ctx) == nil {
+			t.Errorf("goroutine span not in context")
+		}
+		return nil
+	})
+	g.Go("fetch.stats", func(ctx context.Context) error {
+		return errors.New("stats unavailable")
+	})
+	if err := g.Wait(); err == nil || err.Error() != "stats unavailable" {
+		t.Errorf("incorrect error: %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("group context not cancelled")
+	}
+	root.Finish()
+
+	parent := recorder.RequireSpan(t, "GET /dashboard")
+	orders := recorder.RequireSpan(t, "fetch.orders")
+	stats := recorder.RequireSpan(t, "fetch.stats")
+	tracetest.AssertChildOf(t, orders, parent)
+	tracetest.AssertChildOf(t, stats, parent)
+	tracetest.AssertError(t, orders, false)
+	tracetest.AssertError(t, stats, true)
+	tracetest.AssertTags(t, orders, map[string]interface{}{"component": "async"})
+}
+
+func TestGroupAgent(t *testing.T) {
+	agent := fakeagent.New()
+	defer agent.Close()
+	tr := opentracer.NewTracer("orders", opentracer.WithStartOptions(ddtracer.WithAgentAddr(agent.Addr())))
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	opentracing.SetGlobalTracer(tr)
+
+	g, _ := WithContext(context.Background())
+	g.Go("reserve.stock", func(context.Context) error { return nil })
+	g.Wait()
+	tr.Close()
+
+	spans := agent.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("incorrect number of spans sent to agent: %d", len(spans))
+	}
+	if spans[0].Name != "async" || spans[0].Resource != "reserve.stock" {
+		t.Errorf("incorrect span: %+v", spans[0])
+	}
+}
@@ -0,0 +1,32 @@
+package async
+
+import (
+	"github.com/opentracing/opentracing-go"
+)
+
+// config holds optional settings of goroutine spans
+type config struct {
+	tags opentracing.Tags
+}
+
+// Option typed functions can be used to configure goroutine spans
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		tags: opentracing.Tags{},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithTags gives tags set to goroutine spans
+func WithTags(tags opentracing.Tags) Option {
+	return func(cfg *config) {
+		for k, v := range tags {
+			cfg.tags[k] = v
+		}
+	}
+}
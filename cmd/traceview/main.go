@@ -0,0 +1,52 @@
+// Command traceview prints traces written by jsonl tracer as waterfalls.
+//
+// Spans are read from files given as arguments or from stdin:
+//
+//	traceview -errors -min-duration 100ms spans.jsonl
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	f := filter{}
+	flag.StringVar(&f.traceID, "trace", "", "show only trace with given ID")
+	flag.StringVar(&f.service, "service", "", "show only traces with spans of given service")
+	flag.DurationVar(&f.minDuration, "min-duration", 0, "show only traces lasting at least given duration")
+	flag.BoolVar(&f.errors, "errors", false, "show only traces with errors")
+	width := flag.Int("width", 40, "width of waterfall bars")
+	flag.Parse()
+	if *width < 1 {
+		fmt.Fprintln(os.Stderr, "width must be at least 1")
+		os.Exit(2)
+	}
+
+	readers := []io.Reader{}
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		readers = append(readers, file)
+	}
+	if len(readers) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+
+	traces, err := readTraces(io.MultiReader(readers...))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, t := range traces {
+		if f.match(t) {
+			t.print(os.Stdout, *width)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/foodiefm/opentracing/jsonl"
+)
+
+// maxLineSize is maximum size of single span line
+const maxLineSize = 1024 * 1024
+
+// node is span in trace tree
+type node struct {
+	record   jsonl.Record
+	children []*node
+}
+
+// failed returns true, if span has error tag set
+func (n *node) failed() bool {
+	v, _ := n.record.Tags["error"].(bool)
+	return v
+}
+
+// trace is tree of spans with same trace ID
+type trace struct {
+	id    string
+	roots []*node
+	nodes []*node
+	start time.Time
+	end   time.Time
+}
+
+// duration returns time between start of first span
+// and end of last span of trace
+func (t *trace) duration() time.Duration {
+	return t.end.Sub(t.start)
+}
+
+// readTraces reads spans from JSON lines and builds traces ordered
+// by their start time. Children of spans are ordered by start time.
+// Spans, which parents are not found, are shown as roots.
+func readTraces(r io.Reader) ([]*trace, error) {
+	byID := map[string]*trace{}
+	traces := []*trace{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxLineSize)
+	line := 0
+	for s.Scan() {
+		line++
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		n := &node{}
+		if err := json.Unmarshal(s.Bytes(), &n.record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		t, ok := byID[n.record.TraceID]
+		if !ok {
+			t = &trace{id: n.record.TraceID}
+			byID[t.id] = t
+			traces = append(traces, t)
+		}
+		t.nodes = append(t.nodes, n)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range traces {
+		t.build()
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].start.Before(traces[j].start)
+	})
+	return traces, nil
+}
+
+// build links spans of trace to tree
+func (t *trace) build() {
+	sort.SliceStable(t.nodes, func(i, j int) bool {
+		return t.nodes[i].record.Start.Before(t.nodes[j].record.Start)
+	})
+	spans := map[string]*node{}
+	for _, n := range t.nodes {
+		spans[n.record.SpanID] = n
+	}
+	for i, n := range t.nodes {
+		if parent, ok := spans[n.record.ParentID]; ok && n.record.ParentID != "" {
+			parent.children = append(parent.children, n)
+		} else {
+			t.roots = append(t.roots, n)
+		}
+		end := n.record.Start.Add(n.record.Duration())
+		if i == 0 || n.record.Start.Before(t.start) {
+			t.start = n.record.Start
+		}
+		if end.After(t.end) {
+			t.end = end
+		}
+	}
+}
+
+// print writes trace as waterfall, where each span is on own line
+// with its duration and bar showing its position in trace.
+// Failed spans are marked with exclamation mark.
+func (t *trace) print(w io.Writer, width int) {
+	fmt.Fprintf(w, "trace %s (%d spans, %v)\n", t.id, len(t.nodes), t.duration())
+
+	var write func(n *node, depth int)
+	write = func(n *node, depth int) {
+		marker := " "
+		if n.failed() {
+			marker = "!"
+		}
+		name := n.record.Operation
+		if n.record.Service != "" {
+			name = n.record.Service + " " + name
+		}
+		fmt.Fprintf(w, "%s %-50s %12v |%s|\n",
+			marker, strings.Repeat("  ", depth)+name, n.record.Duration(), t.bar(n, width))
+		for _, child := range n.children {
+			write(child, depth+1)
+		}
+	}
+	for _, root := range t.roots {
+		write(root, 0)
+	}
+	fmt.Fprintln(w)
+}
+
+// bar returns bar of given width, which marks time span of node within trace.
+// Empty bar is returned, if width is not positive.
+func (t *trace) bar(n *node, width int) string {
+	if width < 1 {
+		return ""
+	}
+	total := t.duration()
+	if total <= 0 {
+		return strings.Repeat("=", width)
+	}
+	from := int(int64(n.record.Start.Sub(t.start)) * int64(width) / int64(total))
+	to := int(int64(n.record.Start.Add(n.record.Duration()).Sub(t.start)) * int64(width) / int64(total))
+	if from < 0 {
+		from = 0
+	}
+	if to <= from {
+		to = from + 1
+	}
+	if to > width {
+		to = width
+		if from >= to {
+			from = to - 1
+		}
+	}
+	return strings.Repeat(" ", from) + strings.Repeat("=", to-from) + strings.Repeat(" ", width-to)
+}
+
+// filter selects traces to print
+type filter struct {
+	traceID     string
+	service     string
+	minDuration time.Duration
+	errors      bool
+}
+
+// match returns true, if trace matches all conditions of filter
+func (f filter) match(t *trace) bool {
+	if f.traceID != "" && !strings.EqualFold(strings.TrimLeft(f.traceID, "0"), t.id) {
+		return false
+	}
+	if t.duration() < f.minDuration {
+		return false
+	}
+	service, failed := f.service == "", !f.errors
+	for _, n := range t.nodes {
+		service = service || n.record.Service == f.service
+		failed = failed || n.failed()
+	}
+	return service && failed
+}
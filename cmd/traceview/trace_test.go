@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/foodiefm/opentracing/jsonl"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// spans writes two traces, first of which has failed child span
+func spans(t *testing.T) *bytes.Buffer {
+	b := &bytes.Buffer{}
+	orders := jsonl.New(b, jsonl.WithServiceName("orders"))
+	payments := jsonl.New(b, jsonl.WithServiceName("payments"))
+
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) opentracing.StartTime {
+		return opentracing.StartTime(start.Add(time.Duration(ms) * time.Millisecond))
+	}
+	finish := func(span opentracing.Span, ms int) {
+		span.FinishWithOptions(opentracing.FinishOptions{FinishTime: start.Add(time.Duration(ms) * time.Millisecond)})
+	}
+
+	root := orders.StartSpan("GET /orders", at(0))
+	query := orders.StartSpan("sql.query", opentracing.ChildOf(root.Context()), at(10))
+	finish(query, 30)
+	charge := payments.StartSpan("POST /charge", opentracing.ChildOf(root.Context()), at(40))
+	ext.Error.Set(charge, true)
+	finish(charge, 80)
+	finish(root, 80)
+
+	health := orders.StartSpan("GET /health", at(100))
+	finish(health, 101)
+	return b
+}
+
+func TestReadTraces(t *testing.T) {
+	traces, err := readTraces(spans(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("incorrect number of traces: %d", len(traces))
+	}
+	tr := traces[0]
+	if len(tr.roots) != 1 || len(tr.roots[0].children) != 2 {
+		t.Fatalf("incorrect trace tree")
+	}
+	if tr.roots[0].children[0].record.Operation != "sql.query" {
+		t.Errorf("children not ordered by start time")
+	}
+	if tr.duration() != 80*time.Millisecond {
+		t.Errorf("incorrect trace duration: %v", tr.duration())
+	}
+
+	b := &bytes.Buffer{}
+	tr.print(b, 8)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("incorrect waterfall:\n%s", b.String())
+	}
+	expected := []string{"|========|", "| ==     |", "|    ====|"}
+	for i, line := range lines[1:] {
+		if !strings.HasSuffix(line, expected[i]) {
+			t.Errorf("incorrect bar on line '%s', expected: %s", line, expected[i])
+		}
+	}
+	if !strings.HasPrefix(lines[3], "!   payments POST /charge") {
+		t.Errorf("failed span not marked: '%s'", lines[3])
+	}
+}
+
+func TestTraceBar(t *testing.T) {
+	traces, err := readTraces(spans(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tr := traces[0]
+	early := &node{record: tr.roots[0].record}
+	early.record.Start = tr.start.Add(-10 * time.Millisecond)
+
+	tests := []struct {
+		name  string
+		n     *node
+		width int
+		want  string
+	}{
+		{"root", tr.roots[0], 4, "===="},
+		{"zero width", tr.roots[0], 0, ""},
+		{"negative width", tr.roots[0], -1, ""},
+		{"starts before trace", early, 8, "======= "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tr.bar(tt.n, tt.width); got != tt.want {
+				t.Errorf("bar() = '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	traces, err := readTraces(spans(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		filter  filter
+		matches int
+	}{
+		{"no filter", filter{}, 2},
+		{"trace ID", filter{traceID: strings.ToUpper(traces[1].id)}, 1},
+		{"service", filter{service: "payments"}, 1},
+		{"unknown service", filter{service: "users"}, 0},
+		{"min duration", filter{minDuration: 50 * time.Millisecond}, 1},
+		{"errors", filter{errors: true}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := 0
+			for _, tr := range traces {
+				if test.filter.match(tr) {
+					matches++
+				}
+			}
+			if matches != test.matches {
+				t.Errorf("incorrect number of matches: %d, expected: %d", matches, test.matches)
+			}
+		})
+	}
+}
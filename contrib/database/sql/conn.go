@@ -0,0 +1,176 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// errNamedParameters is returned, when driver does not support named parameters
+var errNamedParameters = errors.New("sql: driver does not support the use of Named Parameters")
+
+// conn traces operations of wrapped connection
+type conn struct {
+	driver.Conn
+	cfg *config
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	span := c.cfg.startSpan(ctx, operationPrepare, query)
+	var s driver.Stmt
+	var err error
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = pc.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	finishSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: s, query: query, cfg: c.cfg}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, isQueryerContext := c.Conn.(driver.QueryerContext)
+	q, isQueryer := c.Conn.(driver.Queryer)
+	if !isQueryerContext && !isQueryer {
+		return nil, driver.ErrSkip
+	}
+
+	// span is started after call, as driver may skip call
+	// and database/sql retries it with prepared statement
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if isQueryerContext {
+		rows, err = qc.QueryContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = q.Query(query, values)
+		}
+	}
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	span := c.cfg.startSpan(ctx, operationQuery, query, opentracing.StartTime(start))
+	finishSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return c.cfg.wrapRows(ctx, rows, query), nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, isExecerContext := c.Conn.(driver.ExecerContext)
+	e, isExecer := c.Conn.(driver.Execer)
+	if !isExecerContext && !isExecer {
+		return nil, driver.ErrSkip
+	}
+
+	// span is started after call, as driver may skip call
+	// and database/sql retries it with prepared statement
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if isExecerContext {
+		res, err = ec.ExecContext(ctx, query, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = e.Exec(query, values)
+		}
+	}
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	span := c.cfg.startSpan(ctx, operationExec, query, opentracing.StartTime(start))
+	setRowsAffected(span, res)
+	finishSpan(span, err)
+	return res, err
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	span := c.cfg.startSpan(ctx, operationBegin, "")
+	var t driver.Tx
+	var err error
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		t, err = bc.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
+		err = errors.New("sql: driver does not support non-default isolation level or read-only transactions")
+	} else {
+		t, err = c.Conn.Begin()
+	}
+	finishSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tx{Tx: t, ctx: ctx, cfg: c.cfg}, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+// tx traces commit and rollback with context of transaction
+type tx struct {
+	driver.Tx
+	ctx context.Context
+	cfg *config
+}
+
+func (t *tx) Commit() error {
+	span := t.cfg.startSpan(t.ctx, operationCommit, "")
+	err := t.Tx.Commit()
+	finishSpan(span, err)
+	return err
+}
+
+func (t *tx) Rollback() error {
+	span := t.cfg.startSpan(t.ctx, operationRollback, "")
+	err := t.Tx.Rollback()
+	finishSpan(span, err)
+	return err
+}
+
+// namedValuesToValues converts arguments for drivers,
+// which do not support context aware methods
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errNamedParameters
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
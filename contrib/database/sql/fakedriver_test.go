@@ -0,0 +1,207 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// errQuery is returned by fake driver for queries containing "fail"
+var errQuery = errors.New("query failed")
+
+// errNotConverted is returned by fake statement, if
+// arguments are not converted with its column converter
+var errNotConverted = errors.New("arguments not converted")
+
+// fakeDriver opens connections, which don't implement context aware
+// interfaces, unless data source name is "context"
+type fakeDriver struct{}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	if name == "context" {
+		return &fakeContextConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+// fakeConnector opens connections with fake driver
+type fakeConnector struct {
+	name string
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeDriver{}.Open(c.name)
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+// fakeConn implements only required methods of driver.Conn
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+// fakeContextConn implements context aware interfaces
+type fakeContextConn struct {
+	fakeConn
+}
+
+func (c *fakeContextConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Prepare(query)
+}
+
+// QueryContext skips queries containing "skip", so that they are prepared
+func (c *fakeContextConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "skip") {
+		return nil, driver.ErrSkip
+	}
+	return query3(query)
+}
+
+// ExecContext skips statements containing "skip", so that they are prepared
+func (c *fakeContextConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "skip") {
+		return nil, driver.ErrSkip
+	}
+	return exec2(query)
+}
+
+func (c *fakeContextConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+// fakeStmt executes queries of fake driver
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+// Exec fails, if arguments are not strings converted by column converter
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	for _, arg := range args {
+		if _, ok := arg.(string); !ok {
+			return nil, errNotConverted
+		}
+	}
+	return exec2(s.query)
+}
+
+// ColumnConverter converts all arguments to strings
+func (s *fakeStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return stringConverter{}
+}
+
+type stringConverter struct{}
+
+func (stringConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	return fmt.Sprint(v), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return query3(s.query)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+// query3 returns three rows, unless query fails
+func query3(query string) (driver.Rows, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errQuery
+	}
+	return &fakeRows{count: 3, sets: 2}, nil
+}
+
+// exec2 affects two rows, unless statement fails
+func exec2(query string) (driver.Result, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errQuery
+	}
+	return driver.RowsAffected(2), nil
+}
+
+// fakeRows returns given number of rows with id column in each result set
+type fakeRows struct {
+	count int
+	next  int
+	sets  int
+}
+
+func (r *fakeRows) HasNextResultSet() bool {
+	return r.sets > 1
+}
+
+func (r *fakeRows) NextResultSet() error {
+	if r.sets <= 1 {
+		return io.EOF
+	}
+	r.sets--
+	r.next = 0
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeScanType(index int) reflect.Type {
+	return reflect.TypeOf(int64(0))
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return "BIGINT"
+}
+
+func (r *fakeRows) ColumnTypeNullable(index int) (bool, bool) {
+	return false, true
+}
+
+func (r *fakeRows) ColumnTypeLength(index int) (int64, bool) {
+	return 8, true
+}
+
+func (r *fakeRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	return 19, 0, true
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= r.count {
+		return io.EOF
+	}
+	r.next++
+	dest[0] = int64(r.next)
+	return nil
+}
@@ -0,0 +1,74 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/foodiefm/opentracing/utils"
+)
+
+// config holds optional settings of wrapped drivers
+type config struct {
+	dbType      string
+	dbInstance  string
+	peerService string
+	sanitize    func(string) string
+}
+
+// Option typed functions can be used to configure wrapped drivers
+type Option func(*config)
+
+func newConfig(dbType string, opts ...Option) *config {
+	cfg := &config{
+		dbType: dbType,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.sanitize == nil {
+		cfg.sanitize = defaultSanitizer(cfg.dbType)
+	}
+	return cfg
+}
+
+// defaultSanitizer returns obfuscator of statements, which handles
+// backslash escapes in string literals of MySQL and MariaDB
+func defaultSanitizer(dbType string) func(string) string {
+	switch strings.ToLower(dbType) {
+	case "mysql", "mariadb":
+		return utils.SQLObfuscator{BackslashEscapes: true}.Obfuscate
+	}
+	return utils.ObfuscateSQL
+}
+
+// WithDBType gives database type tagged to spans e.g. "postgres".
+// Name of wrapped driver is used by default.
+func WithDBType(name string) Option {
+	return func(cfg *config) {
+		cfg.dbType = name
+	}
+}
+
+// WithDBInstance gives name of database tagged to spans
+func WithDBInstance(name string) Option {
+	return func(cfg *config) {
+		cfg.dbInstance = name
+	}
+}
+
+// WithPeerService sets name of database service to spans
+func WithPeerService(name string) Option {
+	return func(cfg *config) {
+		cfg.peerService = name
+	}
+}
+
+// WithStatementSanitizer gives function, which removes sensitive data
+// from statements before they are tagged to spans. By default literals
+// are replaced with utils.ObfuscateSQL or, if database type is "mysql"
+// or "mariadb", with utils.SQLObfuscator{BackslashEscapes: true}.Obfuscate.
+// Length of statements can be limited with utils.SQLObfuscator{MaxLength: n}.Obfuscate.
+func WithStatementSanitizer(sanitize func(string) string) Option {
+	return func(cfg *config) {
+		cfg.sanitize = sanitize
+	}
+}
@@ -0,0 +1,77 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+// Operation names of spans
+const (
+	operationQuery    = "sql.query"
+	operationExec     = "sql.exec"
+	operationPrepare  = "sql.prepare"
+	operationBegin    = "sql.begin"
+	operationCommit   = "sql.commit"
+	operationRollback = "sql.rollback"
+	operationRows     = "sql.rows"
+)
+
+const (
+	// component is component tag of database spans
+	component = "database/sql"
+	// tagRowsAffected is tag for rows affected by statement
+	tagRowsAffected = "db.rows_affected"
+	// tagRows is tag for number of iterated rows
+	tagRows = "db.rows"
+)
+
+// startSpan starts span for database operation. Sanitized query is used as
+// resource and statement of span. Extra options such as start time are added
+// to start options. Nil span is returned, if context has no span.
+func (cfg *config) startSpan(ctx context.Context, operationName, query string, extra ...opentracing.StartSpanOption) opentracing.Span {
+	if ctx == nil || opentracing.SpanFromContext(ctx) == nil {
+		return nil
+	}
+
+	opts := []opentracing.StartSpanOption{
+		ext.SpanKindRPCClient,
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: string(ext.DBType), Value: cfg.dbType},
+		opentracer.SpanTypeOption(ddext.SpanTypeSQL),
+	}
+	opts = append(opts, extra...)
+	if cfg.dbInstance != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.DBInstance), Value: cfg.dbInstance})
+	}
+	if cfg.peerService != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerService), Value: cfg.peerService})
+	}
+	if query != "" {
+		statement := cfg.sanitize(query)
+		opts = append(opts,
+			opentracing.Tag{Key: string(ext.DBStatement), Value: statement},
+			opentracer.ResourceOption(statement))
+	}
+	span, _ := utils.StartSpanFromContext(ctx, operationName, opts...)
+	return span
+}
+
+// finishSpan marks span as error, if operation failed, and finishes it.
+// Skipped operations and end of rows are not errors.
+func finishSpan(span opentracing.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil && err != driver.ErrSkip && err != io.EOF {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+	span.Finish()
+}
@@ -0,0 +1,97 @@
+// Package sql implements database/sql driver wrapper, which traces
+// queries, statements and transactions using opentracing. Like wrapped
+// http clients, spans are started only if context of call contains span,
+// so context aware methods of sql.DB must be used.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+const (
+	// tracedSuffix is appended to names of registered drivers
+	tracedSuffix = ".traced"
+	// defaultDBType is database type of wrapped connectors
+	defaultDBType = "sql"
+)
+
+var (
+	registeredMu sync.Mutex
+	registered   = map[string]bool{}
+)
+
+// Register registers traced version of given driver. Traced driver
+// is opened with Open using same driver name. Repeated registrations
+// of same driver name are ignored.
+func Register(driverName string, d driver.Driver, opts ...Option) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	if registered[driverName] {
+		return
+	}
+	registered[driverName] = true
+	sql.Register(driverName+tracedSuffix, &tracedDriver{
+		Driver: d,
+		cfg:    newConfig(driverName, opts...),
+	})
+}
+
+// Open opens database using traced driver registered with Register
+func Open(driverName, dataSourceName string) (*sql.DB, error) {
+	registeredMu.Lock()
+	ok := registered[driverName]
+	registeredMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("sql: driver %q is not registered with tracing", driverName)
+	}
+	return sql.Open(driverName+tracedSuffix, dataSourceName)
+}
+
+// WrapConnector wraps connector, so that connections opened with it are
+// traced. Wrapped connector is opened with sql.OpenDB. Database type
+// should be given with WithDBType, as connector has no driver name.
+func WrapConnector(c driver.Connector, opts ...Option) driver.Connector {
+	cfg := newConfig(defaultDBType, opts...)
+	return &tracedConnector{
+		Connector: c,
+		driver:    &tracedDriver{Driver: c.Driver(), cfg: cfg},
+		cfg:       cfg,
+	}
+}
+
+// tracedDriver wraps connections opened by driver
+type tracedDriver struct {
+	driver.Driver
+	cfg *config
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, cfg: d.cfg}, nil
+}
+
+// tracedConnector wraps connections opened by connector
+type tracedConnector struct {
+	driver.Connector
+	driver *tracedDriver
+	cfg    *config
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: dc, cfg: c.cfg}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,304 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+)
+
+func init() {
+	Register("fake", fakeDriver{}, WithDBInstance("orders"), WithPeerService("orders-db"))
+}
+
+// query reads all rows of query
+func query(ctx context.Context, db *sql.DB, q string) error {
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		rows.Scan(&id)
+	}
+	return rows.Err()
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("fake", "context"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := Open("unknown", ""); err == nil {
+		t.Errorf("expected error for unregistered driver")
+	}
+}
+
+func TestDriver(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []string
+		run        func(ctx context.Context, db *sql.DB) error
+		tags       map[string]map[string]interface{}
+		failed     bool
+		// prepared is true, if database/sql prepares statement
+		// for drivers without context aware query and exec
+		prepared bool
+	}{
+		{
+			name:       "query",
+			prepared:   true,
+			operations: []string{"sql.query", "sql.rows"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				return query(ctx, db, "SELECT id\n\t  FROM orders WHERE total > 100")
+			},
+			tags: map[string]map[string]interface{}{
+				"sql.query": {"db.statement": "SELECT id FROM orders WHERE total > ?", "resource.name": "SELECT id FROM orders WHERE total > ?"},
+				"sql.rows":  {"db.rows": 3},
+			},
+		},
+		{
+			name:       "failed query",
+			prepared:   true,
+			operations: []string{"sql.query"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				return query(ctx, db, "SELECT fail")
+			},
+			failed: true,
+		},
+		{
+			name:       "exec",
+			prepared:   true,
+			operations: []string{"sql.exec"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "DELETE FROM orders")
+				return err
+			},
+			tags: map[string]map[string]interface{}{
+				"sql.exec": {"db.rows_affected": 2, "db.statement": "DELETE FROM orders"},
+			},
+		},
+		{
+			name:       "prepared statement",
+			operations: []string{"sql.prepare", "sql.exec"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				stmt, err := db.PrepareContext(ctx, "UPDATE orders SET state = ?")
+				if err != nil {
+					return err
+				}
+				defer stmt.Close()
+				_, err = stmt.ExecContext(ctx, "paid")
+				return err
+			},
+			tags: map[string]map[string]interface{}{
+				"sql.prepare": {"db.statement": "UPDATE orders SET state = ?"},
+				"sql.exec":    {"db.statement": "UPDATE orders SET state = ?"},
+			},
+		},
+		{
+			name:       "transaction",
+			prepared:   true,
+			operations: []string{"sql.begin", "sql.exec", "sql.commit"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				tx, err := db.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, "DELETE FROM orders"); err != nil {
+					return err
+				}
+				return tx.Commit()
+			},
+		},
+		{
+			name:       "rollback",
+			operations: []string{"sql.begin", "sql.rollback"},
+			run: func(ctx context.Context, db *sql.DB) error {
+				tx, err := db.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				return tx.Rollback()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for _, dsn := range []string{"context", "legacy"} {
+			t.Run(test.name+" "+dsn, func(t *testing.T) {
+				recorder, done := tracetest.Global()
+				defer done()
+				db, err := Open("fake", dsn)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				defer db.Close()
+
+				root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+				err = test.run(ctx, db)
+				root.Finish()
+				if (err != nil) != test.failed {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				operations := test.operations
+				if dsn == "legacy" && test.prepared {
+					operations = append([]string{"sql.prepare"}, operations...)
+				}
+				if !recorder.AssertSpanCount(t, len(operations)+1) {
+					return
+				}
+				rootSpan := recorder.RequireSpan(t, "root")
+				for i, operation := range operations {
+					span := recorder.RequireSpan(t, operation)
+					tracetest.AssertChildOf(t, span, rootSpan)
+					tracetest.AssertError(t, span, test.failed && i == len(operations)-1)
+					tracetest.AssertTags(t, span, map[string]interface{}{
+						"db.type":      "fake",
+						"db.instance":  "orders",
+						"peer.service": "orders-db",
+						"span.type":    "sql",
+					})
+					tracetest.AssertTags(t, span, test.tags[operation])
+				}
+			})
+		}
+	}
+}
+
+func TestDriverWithoutSpan(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	db := sql.OpenDB(WrapConnector(fakeConnector{"context"}, WithDBType("postgres")))
+	defer db.Close()
+
+	if err := query(context.Background(), db, "SELECT id FROM orders"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorder.AssertSpanCount(t, 0)
+}
+
+func TestWrapConnector(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	db := sql.OpenDB(WrapConnector(fakeConnector{"context"}, WithDBType("postgres")))
+	defer db.Close()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	root.SetBaggageItem("tenant_id", "42")
+	ctx = utils.ContextWithBaggageTags(ctx, "tenant_id")
+	if err := query(ctx, db, "SELECT id FROM orders"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Finish()
+
+	tracetest.AssertTags(t, recorder.RequireSpan(t, "sql.query"), map[string]interface{}{
+		"db.type":   "postgres",
+		"tenant_id": "42",
+	})
+}
+
+func TestDriverSkip(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	db, err := Open("fake", "context")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	if _, err := db.ExecContext(ctx, "DELETE FROM orders -- skip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Finish()
+
+	// skipped exec is not traced, only prepared statement and its execution
+	recorder.AssertSpanCount(t, 3)
+	if spans := recorder.FindSpans("sql.exec"); len(spans) != 1 {
+		t.Errorf("incorrect number of exec spans: %d", len(spans))
+	}
+	recorder.RequireSpan(t, "sql.prepare")
+}
+
+func TestRowsResultSetsAndColumnTypes(t *testing.T) {
+	_, done := tracetest.Global()
+	defer done()
+	db, err := Open("fake", "context")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	defer root.Finish()
+	rows, err := db.QueryContext(ctx, "SELECT id FROM orders; SELECT id FROM items")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ct := types[0]
+	length, hasLength := ct.Length()
+	nullable, hasNullable := ct.Nullable()
+	precision, _, hasPrecision := ct.DecimalSize()
+	if ct.DatabaseTypeName() != "BIGINT" || ct.ScanType() != reflect.TypeOf(int64(0)) ||
+		length != 8 || !hasLength || nullable || !hasNullable || precision != 19 || !hasPrecision {
+		t.Errorf("column type not forwarded: %+v", ct)
+	}
+
+	sets := 0
+	for {
+		for rows.Next() {
+		}
+		sets++
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if sets != 2 {
+		t.Errorf("incorrect number of result sets: %d", sets)
+	}
+}
+
+func TestStmtColumnConverter(t *testing.T) {
+	db, err := Open("fake", "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("DELETE FROM orders WHERE id = ?", 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDefaultSanitizer(t *testing.T) {
+	query := `SELECT id FROM orders WHERE note = 'it\'s -- paid' AND total > 100`
+	tests := []struct {
+		dbType   string
+		expected string
+	}{
+		{"postgres", "SELECT id FROM orders WHERE note = ?s"},
+		{"mysql", "SELECT id FROM orders WHERE note = ? AND total > ?"},
+		{"MariaDB", "SELECT id FROM orders WHERE note = ? AND total > ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			if got := newConfig("sql", WithDBType(tt.dbType)).sanitize(query); got != tt.expected {
+				t.Errorf("incorrect statement: '%s', expected: '%s'", got, tt.expected)
+			}
+		})
+	}
+	if got := newConfig("mysql", WithStatementSanitizer(utils.ObfuscateSQL)).sanitize(query); got != utils.ObfuscateSQL(query) {
+		t.Errorf("custom sanitizer not used: '%s'", got)
+	}
+}
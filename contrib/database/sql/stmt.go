@@ -0,0 +1,181 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// stmt traces executions of prepared statement
+type stmt struct {
+	driver.Stmt
+	query string
+	cfg   *config
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.Stmt.Exec(args)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.Stmt.Query(args)
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	span := s.cfg.startSpan(ctx, operationExec, s.query)
+	var res driver.Result
+	var err error
+	if sc, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = sc.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	setRowsAffected(span, res)
+	finishSpan(span, err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	span := s.cfg.startSpan(ctx, operationQuery, s.query)
+	var rows driver.Rows
+	var err error
+	if sc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = sc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	finishSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return s.cfg.wrapRows(ctx, rows, s.query), nil
+}
+
+func (s *stmt) CheckNamedValue(v *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+// ColumnConverter returns converter of wrapped statement or default
+// converter, which database/sql uses when statement has no converter
+func (s *stmt) ColumnConverter(idx int) driver.ValueConverter {
+	if cc, ok := s.Stmt.(driver.ColumnConverter); ok {
+		return cc.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+// setRowsAffected tags number of rows affected by statement
+func setRowsAffected(span opentracing.Span, res driver.Result) {
+	if span == nil || res == nil {
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		span.SetTag(tagRowsAffected, n)
+	}
+}
+
+// wrapRows starts span for iteration of rows, which
+// is finished when rows are closed
+func (cfg *config) wrapRows(ctx context.Context, r driver.Rows, query string) driver.Rows {
+	span := cfg.startSpan(ctx, operationRows, query)
+	if span == nil {
+		return r
+	}
+	return &rows{Rows: r, span: span}
+}
+
+// rows counts iterated rows and finishes span, when rows are closed.
+// Optional interfaces of wrapped rows are forwarded and methods return
+// same values as database/sql uses, when rows don't implement them.
+type rows struct {
+	driver.Rows
+	span  opentracing.Span
+	once  sync.Once
+	count int
+	err   error
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.count++
+	} else {
+		r.err = err
+	}
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		r.span.SetTag(tagRows, r.count)
+		if err != nil {
+			finishSpan(r.span, err)
+		} else {
+			finishSpan(r.span, r.err)
+		}
+	})
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *rows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *rows) ColumnTypeLength(index int) (int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *rows) ColumnTypeNullable(index int) (bool, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *rows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
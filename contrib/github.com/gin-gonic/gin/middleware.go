@@ -3,10 +3,12 @@ package gin
 import (
 	"context"
 
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
 	"github.com/foodiefm/opentracing/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
 )
 
 // Inject typed functions can be used to inject
@@ -15,7 +17,8 @@ type Inject func(context.Context, opentracing.Span) context.Context
 
 // RequestTracer implements gin middleware to trace requests
 // using opentracing
-func RequestTracer(inject Inject) gin.HandlerFunc {
+func RequestTracer(inject Inject, opts ...Option) gin.HandlerFunc {
+	cfg := newConfig(opts...)
 	return func(c *gin.Context) {
 		if opentracing.IsGlobalTracerRegistered() {
 			req := c.Request
@@ -38,6 +41,7 @@ func RequestTracer(inject Inject) gin.HandlerFunc {
 				req.Context(),
 				utils.GetResourceName(req, params),
 				ext.RPCServerOption(wireContext),
+				opentracer.SpanTypeOption(ddext.SpanTypeWeb),
 			)
 
 			// Ensure that span is finished and return status is added to it
@@ -54,10 +58,14 @@ func RequestTracer(inject Inject) gin.HandlerFunc {
 			// Add tags to span
 			ext.HTTPMethod.Set(serverSpan, req.Method)
 			ext.HTTPUrl.Set(serverSpan, req.URL.Path)
-			serverSpan.SetTag("span.type", "web")
+
+			// Add baggage items from request headers and promote them to tags
+			utils.BaggageFromHeaders(serverSpan, req.Header, cfg.baggageHeaders, cfg.baggageLimits)
+			utils.PromoteBaggage(serverSpan, cfg.baggageTags...)
 
 			// Add span to Request object Context
 			ctx = opentracing.ContextWithSpan(ctx, serverSpan)
+			ctx = utils.ContextWithBaggageTags(ctx, cfg.baggageTags...)
 
 			// Inject specific handling to spans
 			if inject != nil {
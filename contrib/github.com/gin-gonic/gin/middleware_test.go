@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/foodiefm/opentracing/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
@@ -120,3 +121,47 @@ func TestRequestTracer(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestTracerBaggage(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	mockTracer := mocktracer.New()
+	opentracing.SetGlobalTracer(mockTracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	router := gin.New()
+	router.Use(RequestTracer(nil,
+		WithBaggageHeaders(map[string]string{"X-Tenant-ID": "tenant_id", "X-Experiment": "experiment"}),
+		WithBaggageTags("tenant_id"),
+		WithBaggageLimits(utils.BaggageLimits{MaxItemSize: 16}),
+	))
+	router.GET("/test", func(c *gin.Context) {
+		span := opentracing.SpanFromContext(c.Request.Context())
+		if span.BaggageItem("tenant_id") != "42" {
+			t.Errorf("baggage item not set from header")
+		}
+		if span.BaggageItem("experiment") != "" {
+			t.Errorf("baggage item exceeding limits was set")
+		}
+		if keys := utils.BaggageTagsFromContext(c.Request.Context()); len(keys) != 1 {
+			t.Errorf("baggage tags not stored in context")
+		}
+		c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Tenant-ID", "42")
+	req.Header.Set("X-Experiment", "new-checkout-flow")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := mockTracer.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("incorrect number of spans")
+	}
+	if v := spans[0].Tag("tenant_id"); v != "42" {
+		t.Errorf("baggage item not promoted to tag: %v", v)
+	}
+	if v := spans[0].Tag("span.type"); v != "web" {
+		t.Errorf("incorrect span type: %v", v)
+	}
+}
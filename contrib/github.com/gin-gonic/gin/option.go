@@ -0,0 +1,47 @@
+package gin
+
+import (
+	"github.com/foodiefm/opentracing/utils"
+)
+
+// config holds optional settings of RequestTracer
+type config struct {
+	baggageHeaders map[string]string
+	baggageTags    []string
+	baggageLimits  utils.BaggageLimits
+}
+
+// Option typed functions can be used to configure RequestTracer
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithBaggageHeaders sets values of incoming request headers as baggage
+// items of server span. Headers maps header names to baggage keys,
+// e.g. "X-Tenant-ID": "tenant_id".
+func WithBaggageHeaders(headers map[string]string) Option {
+	return func(cfg *config) {
+		cfg.baggageHeaders = headers
+	}
+}
+
+// WithBaggageTags promotes given baggage items to tags of server span
+// and spans started beneath it by integrations of this module.
+func WithBaggageTags(keys ...string) Option {
+	return func(cfg *config) {
+		cfg.baggageTags = keys
+	}
+}
+
+// WithBaggageLimits limits baggage items set from incoming request headers
+func WithBaggageLimits(limits utils.BaggageLimits) Option {
+	return func(cfg *config) {
+		cfg.baggageLimits = limits
+	}
+}
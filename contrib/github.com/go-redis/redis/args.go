@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/foodiefm/opentracing/utils"
+)
+
+// keyArgs tells how keys of command are positioned in arguments.
+// Commands, which are not listed, have one key as first argument.
+var keyArgs = map[string]struct {
+	// first is index of first key argument, zero means no keys
+	first int
+	// step is distance between keys, zero means single key
+	step int
+}{
+	"ping":         {},
+	"echo":         {},
+	"auth":         {},
+	"select":       {},
+	"info":         {},
+	"dbsize":       {},
+	"flushdb":      {},
+	"flushall":     {},
+	"time":         {},
+	"multi":        {},
+	"exec":         {},
+	"discard":      {},
+	"unwatch":      {},
+	"scan":         {},
+	"keys":         {},
+	"randomkey":    {},
+	"publish":      {},
+	"eval":         {},
+	"evalsha":      {},
+	"client":       {},
+	"config":       {},
+	"script":       {},
+	"del":          {1, 1},
+	"unlink":       {1, 1},
+	"exists":       {1, 1},
+	"touch":        {1, 1},
+	"watch":        {1, 1},
+	"mget":         {1, 1},
+	"sunion":       {1, 1},
+	"sinter":       {1, 1},
+	"sdiff":        {1, 1},
+	"pfcount":      {1, 1},
+	"mset":         {1, 2},
+	"msetnx":       {1, 2},
+	"rename":       {1, 1},
+	"renamenx":     {1, 1},
+	"smove":        {1, 1},
+	"rpoplpush":    {1, 1},
+	"sunionstore":  {1, 1},
+	"sinterstore":  {1, 1},
+	"sdiffstore":   {1, 1},
+	"pfmerge":      {1, 1},
+	"zunionstore":  {1, 0},
+	"zinterstore":  {1, 0},
+	"bitop":        {2, 1},
+	"blpop":        {1, 1},
+	"brpop":        {1, 1},
+	"brpoplpush":   {1, 1},
+	"bzpopmin":     {1, 1},
+	"bzpopmax":     {1, 1},
+	"xread":        {},
+	"xreadgroup":   {},
+	"subscribe":    {},
+	"psubscribe":   {},
+	"unsubscribe":  {},
+	"punsubscribe": {},
+}
+
+// keyPositions returns indexes of key arguments of command.
+// Blocking commands have timeout as last argument.
+func keyPositions(name string, args []interface{}) []int {
+	spec, ok := keyArgs[name]
+	if !ok {
+		if len(args) > 1 {
+			return []int{1}
+		}
+		return nil
+	}
+	if spec.first == 0 || spec.first >= len(args) {
+		return nil
+	}
+	if spec.step == 0 {
+		return []int{spec.first}
+	}
+
+	last := len(args)
+	switch name {
+	case "blpop", "brpop", "brpoplpush", "bzpopmin", "bzpopmax":
+		last--
+	case "smove":
+		last = 3
+	}
+	positions := []int{}
+	for i := spec.first; i < last && i < len(args); i += spec.step {
+		positions = append(positions, i)
+	}
+	return positions
+}
+
+// obfuscateArgs returns command with its keys, where other
+// arguments are replaced with "?", e.g. "set orders:1 ?"
+func obfuscateArgs(name string, args []interface{}, maxLength int) string {
+	keys := map[int]bool{}
+	for _, i := range keyPositions(name, args) {
+		keys[i] = true
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(name)
+	for i := 1; i < len(args); i++ {
+		b.WriteByte(' ')
+		if keys[i] {
+			fmt.Fprint(b, args[i])
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return utils.Truncate(b.String(), maxLength)
+}
@@ -0,0 +1,56 @@
+package redis
+
+// defaultMaxArgsLength is default maximum length of argument strings
+const defaultMaxArgsLength = 200
+
+// config holds optional settings of hook
+type config struct {
+	dbIndex       int
+	addr          string
+	peerService   string
+	maxArgsLength int
+}
+
+// Option typed functions can be used to configure hook
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		maxArgsLength: defaultMaxArgsLength,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithDBIndex gives index of selected database tagged to spans.
+// WrapClient sets index from options of client.
+func WithDBIndex(index int) Option {
+	return func(cfg *config) {
+		cfg.dbIndex = index
+	}
+}
+
+// WithAddr gives address of Redis server tagged to spans.
+// WrapClient sets address from options of client.
+func WithAddr(addr string) Option {
+	return func(cfg *config) {
+		cfg.addr = addr
+	}
+}
+
+// WithPeerService sets name of Redis service to spans
+func WithPeerService(name string) Option {
+	return func(cfg *config) {
+		cfg.peerService = name
+	}
+}
+
+// WithMaxArgsLength gives maximum length of obfuscated arguments
+// tagged to spans. Default length is 200, zero means no limit.
+func WithMaxArgsLength(length int) Option {
+	return func(cfg *config) {
+		cfg.maxArgsLength = length
+	}
+}
@@ -0,0 +1,148 @@
+// Package redis implements go-redis hook, which traces commands and
+// pipelines using opentracing. Like wrapped http clients, spans are
+// started only if context of command contains span, so commands must
+// be run with client returned by WithContext.
+package redis
+
+import (
+	"context"
+	"strings"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/go-redis/redis/v7"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+const (
+	// component is component tag of Redis spans
+	component = "go-redis"
+	// operationCommand is operation name of command spans
+	operationCommand = "redis.command"
+	// operationPipeline is operation name of pipeline spans
+	operationPipeline = "redis.pipeline"
+
+	tagCommand        = "redis.command"
+	tagKeyCount       = "redis.key_count"
+	tagDBIndex        = "redis.db_index"
+	tagPipelineLength = "redis.pipeline_length"
+)
+
+// spanKey is context key of span started by hook
+type spanKey struct{}
+
+// hook starts spans before commands are processed
+// and finishes them after processing
+type hook struct {
+	cfg *config
+}
+
+// NewHook creates hook tracing commands and pipelines
+func NewHook(opts ...Option) redis.Hook {
+	return &hook{cfg: newConfig(opts...)}
+}
+
+// WrapClient adds tracing hook to client. Database index and
+// address of server are taken from client options.
+func WrapClient(c *redis.Client, opts ...Option) *redis.Client {
+	o := c.Options()
+	opts = append([]Option{WithDBIndex(o.DB), WithAddr(o.Addr)}, opts...)
+	c.AddHook(NewHook(opts...))
+	return c
+}
+
+func (h *hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	name := cmd.Name()
+	args := cmd.Args()
+	span := h.startSpan(ctx, operationCommand, strings.ToUpper(name))
+	if span == nil {
+		return ctx, nil
+	}
+	span.SetTag(tagCommand, name)
+	span.SetTag(tagKeyCount, len(keyPositions(name, args)))
+	ext.DBStatement.Set(span, obfuscateArgs(name, args, h.cfg.maxArgsLength))
+	return context.WithValue(ctx, spanKey{}, span), nil
+}
+
+func (h *hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if span, ok := ctx.Value(spanKey{}).(opentracing.Span); ok {
+		finishSpan(span, cmd.Err())
+	}
+	return nil
+}
+
+func (h *hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = strings.ToUpper(cmd.Name())
+	}
+	span := h.startSpan(ctx, operationPipeline, strings.Join(names, " "))
+	if span == nil {
+		return ctx, nil
+	}
+
+	statements := make([]string, len(cmds))
+	keys := 0
+	for i, cmd := range cmds {
+		statements[i] = obfuscateArgs(cmd.Name(), cmd.Args(), h.cfg.maxArgsLength)
+		keys += len(keyPositions(cmd.Name(), cmd.Args()))
+	}
+	span.SetTag(tagPipelineLength, len(cmds))
+	span.SetTag(tagKeyCount, keys)
+	ext.DBStatement.Set(span, strings.Join(statements, "\n"))
+	return context.WithValue(ctx, spanKey{}, span), nil
+}
+
+// AfterProcessPipeline marks pipeline span as error, if any command failed
+func (h *hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	span, ok := ctx.Value(spanKey{}).(opentracing.Span)
+	if !ok {
+		return nil
+	}
+	var err error
+	for _, cmd := range cmds {
+		if cerr := cmd.Err(); cerr != nil && cerr != redis.Nil {
+			err = cerr
+			break
+		}
+	}
+	finishSpan(span, err)
+	return nil
+}
+
+// startSpan starts span for command or pipeline. Nil span
+// is returned, if context has no span.
+func (h *hook) startSpan(ctx context.Context, operationName, resource string) opentracing.Span {
+	if opentracing.SpanFromContext(ctx) == nil {
+		return nil
+	}
+
+	opts := []opentracing.StartSpanOption{
+		ext.SpanKindRPCClient,
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: string(ext.DBType), Value: "redis"},
+		opentracing.Tag{Key: tagDBIndex, Value: h.cfg.dbIndex},
+		opentracer.SpanTypeOption(ddext.SpanTypeRedis),
+		opentracer.ResourceOption(resource),
+	}
+	if h.cfg.addr != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerAddress), Value: h.cfg.addr})
+	}
+	if h.cfg.peerService != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerService), Value: h.cfg.peerService})
+	}
+	span, _ := utils.StartSpanFromContext(ctx, operationName, opts...)
+	return span
+}
+
+// finishSpan marks span as error, if command failed, and finishes it.
+// Missing key is not an error.
+func finishSpan(span opentracing.Span, err error) {
+	if err != nil && err != redis.Nil {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+	span.Finish()
+}
@@ -0,0 +1,137 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/go-redis/redis/v7"
+	"github.com/opentracing/opentracing-go"
+)
+
+func TestObfuscateArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []interface{}
+		maxLength int
+		want      string
+		keys      int
+	}{
+		{"single key", []interface{}{"set", "orders:1", "secret", "ex", 10}, 0, "set orders:1 ? ? ?", 1},
+		{"no keys", []interface{}{"auth", "password"}, 0, "auth ?", 0},
+		{"no arguments", []interface{}{"ping"}, 0, "ping", 0},
+		{"multiple keys", []interface{}{"del", "a", "b", "c"}, 0, "del a b c", 3},
+		{"key value pairs", []interface{}{"mset", "a", 1, "b", 2}, 0, "mset a ? b ?", 2},
+		{"blocking pop", []interface{}{"blpop", "a", "b", 5}, 0, "blpop a b ?", 2},
+		{"set move", []interface{}{"smove", "a", "b", "member"}, 0, "smove a b ?", 2},
+		{"max length", []interface{}{"get", "orders:123456"}, 10, "get ord...", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.args[0].(string)
+			if got := obfuscateArgs(name, tt.args, tt.maxLength); got != tt.want {
+				t.Errorf("obfuscateArgs() = %v, want %v", got, tt.want)
+			}
+			if got := len(keyPositions(name, tt.args)); got != tt.keys {
+				t.Errorf("key count = %v, want %v", got, tt.keys)
+			}
+		})
+	}
+}
+
+// newClient starts in-memory Redis and returns traced client connected to it
+func newClient(t *testing.T, opts ...Option) (*redis.Client, func()) {
+	t.Helper()
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := WrapClient(redis.NewClient(&redis.Options{Addr: s.Addr(), DB: 0}), opts...)
+	return c, func() {
+		c.Close()
+		s.Close()
+	}
+}
+
+func TestHookCommand(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	client, stop := newClient(t, WithPeerService("cache"))
+	defer stop()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	c := client.WithContext(ctx)
+	if err := c.Set("orders:1", "secret", 0).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get("orders:2").Err(); err != redis.Nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Incr("orders:1").Err(); err == nil {
+		t.Fatalf("expected error")
+	}
+	root.Finish()
+
+	recorder.AssertSpanCount(t, 4)
+	spans := recorder.FindSpans("redis.command")
+	if len(spans) != 3 {
+		t.Fatalf("incorrect number of command spans: %d", len(spans))
+	}
+	for _, span := range spans {
+		tracetest.AssertChildOf(t, span, recorder.RequireSpan(t, "root"))
+		tracetest.AssertTags(t, span, map[string]interface{}{
+			"db.type":         "redis",
+			"redis.db_index":  0,
+			"redis.key_count": 1,
+			"peer.service":    "cache",
+			"span.type":       "redis",
+		})
+	}
+	tracetest.AssertTags(t, spans[0], map[string]interface{}{
+		"redis.command": "set",
+		"resource.name": "SET",
+		"db.statement":  "set orders:1 ?",
+	})
+	tracetest.AssertError(t, spans[0], false)
+	tracetest.AssertError(t, spans[1], false)
+	tracetest.AssertError(t, spans[2], true)
+}
+
+func TestHookPipeline(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	client, stop := newClient(t)
+	defer stop()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	pipe := client.WithContext(ctx).Pipeline()
+	pipe.Set("orders:1", "secret", 0)
+	pipe.MGet("orders:1", "orders:2")
+	if _, err := pipe.Exec(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Finish()
+
+	recorder.AssertSpanCount(t, 2)
+	span := recorder.RequireSpan(t, "redis.pipeline")
+	tracetest.AssertTags(t, span, map[string]interface{}{
+		"resource.name":         "SET MGET",
+		"redis.pipeline_length": 2,
+		"redis.key_count":       3,
+		"db.statement":          "set orders:1 ?\nmget orders:1 orders:2",
+	})
+	tracetest.AssertError(t, span, false)
+}
+
+func TestHookWithoutSpan(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	client, stop := newClient(t)
+	defer stop()
+
+	if err := client.Set("orders:1", "secret", 0).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorder.AssertSpanCount(t, 0)
+}
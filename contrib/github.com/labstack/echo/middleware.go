@@ -3,10 +3,12 @@ package echo
 import (
 	"context"
 
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
 	"github.com/foodiefm/opentracing/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
 )
 
 // Inject typed functions can be used to inject
@@ -14,7 +16,8 @@ import (
 type Inject func(context.Context, opentracing.Span) context.Context
 
 // RequestTracer created middleware to trace requests using opentracing
-func RequestTracer(inject Inject) echo.MiddlewareFunc {
+func RequestTracer(inject Inject, opts ...Option) echo.MiddlewareFunc {
+	cfg := newConfig(opts...)
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			var err error
@@ -39,6 +42,7 @@ func RequestTracer(inject Inject) echo.MiddlewareFunc {
 					req.Context(),
 					utils.GetResourceName(req, params),
 					ext.RPCServerOption(wireContext),
+					opentracer.SpanTypeOption(ddext.SpanTypeWeb),
 				)
 
 				// Ensure that span is finished and return status is added to it
@@ -55,10 +59,14 @@ func RequestTracer(inject Inject) echo.MiddlewareFunc {
 				// Add tags to span
 				ext.HTTPMethod.Set(serverSpan, req.Method)
 				ext.HTTPUrl.Set(serverSpan, req.URL.Path)
-				serverSpan.SetTag("span.type", "web")
+
+				// Add baggage items from request headers and promote them to tags
+				utils.BaggageFromHeaders(serverSpan, req.Header, cfg.baggageHeaders, cfg.baggageLimits)
+				utils.PromoteBaggage(serverSpan, cfg.baggageTags...)
 
 				// Add span to Request object Context
 				ctx = opentracing.ContextWithSpan(ctx, serverSpan)
+				ctx = utils.ContextWithBaggageTags(ctx, cfg.baggageTags...)
 
 				// Inject specific handling to spans
 				if inject != nil {
@@ -0,0 +1,33 @@
+// Package cron implements tracing of jobs scheduled with robfig/cron.
+// Every run of job is traced with jobs.Run.
+package cron
+
+import (
+	"context"
+
+	"github.com/foodiefm/opentracing/jobs"
+	"github.com/robfig/cron/v3"
+)
+
+// JobWrapper returns wrapper, which traces runs of wrapped job
+// as given operation name. It can be used in job chain e.g.
+//
+//	c.AddJob(spec, cron.NewChain(JobWrapper("cleanup")).Then(job))
+func JobWrapper(name string, opts ...jobs.Option) cron.JobWrapper {
+	return func(j cron.Job) cron.Job {
+		return cron.FuncJob(func() {
+			jobs.Run(context.Background(), name, func(context.Context) error {
+				j.Run()
+				return nil
+			}, opts...)
+		})
+	}
+}
+
+// Func returns job, which runs fn with context containing job span.
+// Error returned by fn is tagged to span.
+func Func(name string, fn func(ctx context.Context) error, opts ...jobs.Option) cron.Job {
+	return cron.FuncJob(func() {
+		jobs.Run(context.Background(), name, fn, opts...)
+	})
+}
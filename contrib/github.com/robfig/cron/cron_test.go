@@ -0,0 +1,50 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/robfig/cron/v3"
+)
+
+func TestJobWrapper(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	runs := 0
+	job := cron.NewChain(JobWrapper("cleanup")).Then(cron.FuncJob(func() { runs++ }))
+	job.Run()
+	job.Run()
+
+	if runs != 2 {
+		t.Errorf("incorrect number of runs: %d", runs)
+	}
+	spans := recorder.FindSpans("cleanup")
+	if len(spans) != 2 {
+		t.Fatalf("incorrect number of spans: %d", len(spans))
+	}
+	if spans[0].SpanContext.TraceID == spans[1].SpanContext.TraceID {
+		t.Errorf("runs share trace")
+	}
+	tracetest.AssertTags(t, spans[0], map[string]interface{}{"span.type": "worker", "job.name": "cleanup"})
+	tracetest.AssertError(t, spans[0], false)
+}
+
+func TestFunc(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	Func("sync", func(ctx context.Context) error {
+		if opentracing.SpanFromContext(ctx) == nil {
+			t.Errorf("job span not in context")
+		}
+		return errors.New("upstream down")
+	}).Run()
+
+	span := recorder.RequireSpan(t, "sync")
+	tracetest.AssertTags(t, span, map[string]interface{}{"server.errors": "upstream down"})
+	tracetest.AssertError(t, span, true)
+}
@@ -0,0 +1,217 @@
+// Package kafka implements wrappers of kafka-go readers and writers, which
+// propagate trace context in message headers. Written messages get own
+// producer spans and read messages consumer spans, which follow from
+// producer spans.
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/messaging"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/segmentio/kafka-go"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+const (
+	// component is component tag of Kafka spans
+	component = "kafka-go"
+
+	tagPartition   = "kafka.partition"
+	tagOffset      = "kafka.offset"
+	tagMessageSize = "message.size"
+	tagBatchSize   = "message.batch_size"
+)
+
+// messageWriter writes messages to Kafka
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// messageReader reads messages from Kafka
+type messageReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
+// Writer traces written messages. Other methods
+// are used from embedded kafka-go writer.
+type Writer struct {
+	*kafka.Writer
+	writer messageWriter
+	topic  string
+	cfg    *config
+}
+
+// WrapWriter wraps kafka-go writer. Topic of writer is given with WithTopic.
+func WrapWriter(w *kafka.Writer, opts ...Option) *Writer {
+	cfg := newConfig(opts...)
+	return &Writer{
+		Writer: w,
+		writer: w,
+		topic:  cfg.topic,
+		cfg:    cfg,
+	}
+}
+
+// WriteMessages starts producer span for every message and injects
+// span context to message headers. Headers of given messages are
+// not modified. Spans are finished, when messages are written.
+func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	traced := make([]kafka.Message, len(msgs))
+	spans := make([]opentracing.Span, len(msgs))
+	for i, msg := range msgs {
+		msg.Headers = append([]kafka.Header(nil), msg.Headers...)
+		spans[i], _ = messaging.StartProducerSpan(ctx, w.cfg.producerName, w.topic, messaging.KafkaHeaders(&msg.Headers),
+			opentracing.Tag{Key: string(ext.Component), Value: component},
+			opentracing.Tag{Key: tagMessageSize, Value: messageSize(msg)})
+		traced[i] = msg
+	}
+
+	err := w.writer.WriteMessages(ctx, traced...)
+	for _, span := range spans {
+		finishSpan(span, err)
+	}
+	return err
+}
+
+// Reader traces read messages. Consumer span of message is finished, when
+// next message is read or reader is closed. Other methods are used from
+// embedded kafka-go reader.
+type Reader struct {
+	*kafka.Reader
+	reader messageReader
+	cfg    *config
+
+	mu   sync.Mutex
+	span opentracing.Span
+}
+
+// WrapReader wraps kafka-go reader
+func WrapReader(r *kafka.Reader, opts ...Option) *Reader {
+	return &Reader{
+		Reader: r,
+		reader: r,
+		cfg:    newConfig(opts...),
+	}
+}
+
+// ReadMessage reads message and starts consumer span for it
+func (r *Reader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	r.finishSpan(nil)
+	msg, err := r.reader.ReadMessage(ctx)
+	r.startSpan(ctx, msg, err)
+	return msg, err
+}
+
+// FetchMessage fetches message and starts consumer span for it
+func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.finishSpan(nil)
+	msg, err := r.reader.FetchMessage(ctx)
+	r.startSpan(ctx, msg, err)
+	return msg, err
+}
+
+// Span returns consumer span of last read message or nil, if there
+// is none. Span can be added to context of message processing with
+// opentracing.ContextWithSpan.
+func (r *Reader) Span() opentracing.Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.span
+}
+
+// Close finishes consumer span of last read message and closes reader
+func (r *Reader) Close() error {
+	r.finishSpan(nil)
+	return r.reader.Close()
+}
+
+// startSpan starts consumer span for read message.
+// Spans are not started for failed reads.
+func (r *Reader) startSpan(ctx context.Context, msg kafka.Message, err error) {
+	if err != nil {
+		return
+	}
+	span, _ := r.cfg.startConsumerSpan(ctx, msg)
+	r.mu.Lock()
+	r.span = span
+	r.mu.Unlock()
+}
+
+// finishSpan finishes consumer span of last read message
+func (r *Reader) finishSpan(err error) {
+	r.mu.Lock()
+	span := r.span
+	r.span = nil
+	r.mu.Unlock()
+	if span != nil {
+		finishSpan(span, err)
+	}
+}
+
+// StartConsumerSpan starts consumer span for message, which follows from
+// producer span of message. Span is tagged with topic, partition, offset
+// and size of message. It can be used, when messages are read without
+// wrapped reader.
+func StartConsumerSpan(ctx context.Context, msg kafka.Message, opts ...Option) (opentracing.Span, context.Context) {
+	return newConfig(opts...).startConsumerSpan(ctx, msg)
+}
+
+func (cfg *config) startConsumerSpan(ctx context.Context, msg kafka.Message) (opentracing.Span, context.Context) {
+	return messaging.StartConsumerSpan(ctx, cfg.consumerName, msg.Topic, messaging.KafkaHeadersCarrier(msg.Headers),
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: tagPartition, Value: msg.Partition},
+		opentracing.Tag{Key: tagOffset, Value: msg.Offset},
+		opentracing.Tag{Key: tagMessageSize, Value: messageSize(msg)})
+}
+
+// StartBatchSpan starts consumer span for batch of messages, which follows
+// from producer spans of all messages containing span context. If context
+// contains span, batch span is its child.
+func StartBatchSpan(ctx context.Context, msgs []kafka.Message, opts ...Option) (opentracing.Span, context.Context) {
+	cfg := newConfig(opts...)
+	sopts := []opentracing.StartSpanOption{}
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		sopts = append(sopts, opentracing.ChildOf(parent.Context()))
+	}
+	topics := map[string]bool{}
+	for _, msg := range msgs {
+		topics[msg.Topic] = true
+		sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, messaging.KafkaHeadersCarrier(msg.Headers))
+		if err == nil {
+			sopts = append(sopts, opentracing.FollowsFrom(sc))
+		}
+	}
+	sopts = append(sopts,
+		ext.SpanKindConsumer,
+		opentracer.SpanTypeOption(ddext.SpanTypeMessageConsumer),
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: tagBatchSize, Value: len(msgs)})
+	if len(topics) == 1 && msgs[0].Topic != "" {
+		sopts = append(sopts, opentracing.Tag{Key: string(ext.MessageBusDestination), Value: msgs[0].Topic})
+	}
+
+	span := opentracing.StartSpan(cfg.batchName, sopts...)
+	utils.PromoteContextBaggage(ctx, span)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// messageSize returns size of key and value of message
+func messageSize(msg kafka.Message) int {
+	return len(msg.Key) + len(msg.Value)
+}
+
+// finishSpan marks span as error, if operation failed, and finishes it
+func finishSpan(span opentracing.Span, err error) {
+	if err != nil {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+	span.Finish()
+}
@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeBroker stores written messages and returns them to reader
+type fakeBroker struct {
+	messages []kafka.Message
+	err      error
+	closed   bool
+}
+
+func (b *fakeBroker) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if b.err != nil {
+		return b.err
+	}
+	for _, msg := range msgs {
+		msg.Topic = "orders"
+		msg.Offset = int64(len(b.messages))
+		b.messages = append(b.messages, msg)
+	}
+	return nil
+}
+
+func (b *fakeBroker) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if len(b.messages) == 0 {
+		return kafka.Message{}, io.EOF
+	}
+	msg := b.messages[0]
+	b.messages = b.messages[1:]
+	return msg, nil
+}
+
+func (b *fakeBroker) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	return b.ReadMessage(ctx)
+}
+
+func (b *fakeBroker) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWriterReader(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	broker := &fakeBroker{}
+	w := &Writer{writer: broker, topic: "orders", cfg: newConfig()}
+	r := &Reader{reader: broker, cfg: newConfig(WithConsumerName("orders.consume"))}
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	headers := []kafka.Header{{Key: "content-type", Value: []byte("text/plain")}}
+	msgs := []kafka.Message{
+		{Key: []byte("1"), Value: []byte("created"), Headers: headers},
+		{Key: []byte("2"), Value: []byte("paid")},
+	}
+	if err := w.WriteMessages(ctx, msgs...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Finish()
+	if len(msgs[0].Headers) != 1 || len(headers) != 1 {
+		t.Errorf("headers of given messages modified")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.ReadMessage(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Span() == nil {
+			t.Errorf("consumer span not active")
+		}
+	}
+	if _, err := r.FetchMessage(context.Background()); err != io.EOF {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Span() != nil {
+		t.Errorf("consumer span started for failed read")
+	}
+	r.Close()
+	if !broker.closed {
+		t.Errorf("reader not closed")
+	}
+
+	producers := recorder.FindSpans("kafka.produce")
+	consumers := recorder.FindSpans("orders.consume")
+	if len(producers) != 2 || len(consumers) != 2 {
+		t.Fatalf("incorrect spans:\n%s", recorder.Tree())
+	}
+	for i, producer := range producers {
+		tracetest.AssertChildOf(t, producer, recorder.RequireSpan(t, "root"))
+		tracetest.AssertChildOf(t, consumers[i], producer)
+		tracetest.AssertTags(t, producer, map[string]interface{}{
+			"message_bus.destination": "orders",
+			"span.kind":               "producer",
+			"component":               "kafka-go",
+		})
+		tracetest.AssertTags(t, consumers[i], map[string]interface{}{
+			"message_bus.destination": "orders",
+			"kafka.partition":         0,
+			"kafka.offset":            i,
+			"span.kind":               "consumer",
+		})
+	}
+	tracetest.AssertTags(t, producers[0], map[string]interface{}{"message.size": 8})
+	tracetest.AssertTags(t, consumers[1], map[string]interface{}{"message.size": 5})
+}
+
+func TestWriterError(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	w := &Writer{writer: &fakeBroker{err: errors.New("leader not available")}, topic: "orders", cfg: newConfig()}
+
+	if err := w.WriteMessages(context.Background(), kafka.Message{Value: []byte("created")}); err == nil {
+		t.Fatalf("expected error")
+	}
+	tracetest.AssertError(t, recorder.RequireSpan(t, "kafka.produce"), true)
+}
+
+// referenceTracer records references of started spans
+type referenceTracer struct {
+	*mocktracer.MockTracer
+	references map[string][]opentracing.SpanReference
+}
+
+func (t *referenceTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	sso := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&sso)
+	}
+	t.references[operationName] = sso.References
+	return t.MockTracer.StartSpan(operationName, opts...)
+}
+
+func TestStartBatchSpan(t *testing.T) {
+	tracer := &referenceTracer{MockTracer: mocktracer.New(), references: map[string][]opentracing.SpanReference{}}
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	broker := &fakeBroker{}
+	w := &Writer{writer: broker, topic: "orders", cfg: newConfig()}
+	w.WriteMessages(context.Background(), kafka.Message{Value: []byte("1")})
+	w.WriteMessages(context.Background(), kafka.Message{Value: []byte("2")})
+	broker.messages = append(broker.messages, kafka.Message{Topic: "orders", Value: []byte("untraced")})
+
+	worker, ctx := opentracing.StartSpanFromContext(context.Background(), "worker")
+	span, _ := StartBatchSpan(ctx, broker.messages)
+	span.Finish()
+	worker.Finish()
+
+	refs := tracer.references["kafka.consume_batch"]
+	if len(refs) != 3 {
+		t.Fatalf("incorrect number of references: %d", len(refs))
+	}
+	if refs[0].Type != opentracing.ChildOfRef || refs[1].Type != opentracing.FollowsFromRef || refs[2].Type != opentracing.FollowsFromRef {
+		t.Errorf("incorrect references: %v", refs)
+	}
+	producers := tracer.FinishedSpans()[:2]
+	for i, producer := range producers {
+		if refs[i+1].ReferencedContext.(mocktracer.MockSpanContext).SpanID != producer.SpanContext.SpanID {
+			t.Errorf("batch span does not follow from producer span %d", i)
+		}
+	}
+	tracetest.AssertTags(t, span.(*mocktracer.MockSpan), map[string]interface{}{
+		"message.batch_size":      3,
+		"message_bus.destination": "orders",
+	})
+}
+
+func TestWrapWriter(t *testing.T) {
+	kw := kafka.NewWriter(kafka.WriterConfig{Brokers: []string{"localhost:9092"}, Topic: "orders"})
+	defer kw.Close()
+	if w := WrapWriter(kw, WithTopic("orders")); w.topic != "orders" || w.Writer != kw {
+		t.Errorf("writer not wrapped")
+	}
+}
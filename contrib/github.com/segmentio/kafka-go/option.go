@@ -0,0 +1,60 @@
+package kafka
+
+const (
+	defaultProducerName = "kafka.produce"
+	defaultConsumerName = "kafka.consume"
+	defaultBatchName    = "kafka.consume_batch"
+)
+
+// config holds optional settings of wrapped readers and writers
+type config struct {
+	producerName string
+	consumerName string
+	batchName    string
+	topic        string
+}
+
+// Option typed functions can be used to configure wrapped readers and writers
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		producerName: defaultProducerName,
+		consumerName: defaultConsumerName,
+		batchName:    defaultBatchName,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithProducerName gives operation name of spans of written messages
+func WithProducerName(name string) Option {
+	return func(cfg *config) {
+		cfg.producerName = name
+	}
+}
+
+// WithConsumerName gives operation name of spans of read messages
+func WithConsumerName(name string) Option {
+	return func(cfg *config) {
+		cfg.consumerName = name
+	}
+}
+
+// WithBatchName gives operation name of batch consumption spans
+func WithBatchName(name string) Option {
+	return func(cfg *config) {
+		cfg.batchName = name
+	}
+}
+
+// WithTopic gives topic of wrapped writer, which is tagged as destination
+// of written messages. It is not read from writer, as its Stats resets
+// statistics collected since previous call.
+func WithTopic(topic string) Option {
+	return func(cfg *config) {
+		cfg.topic = topic
+	}
+}
@@ -0,0 +1,107 @@
+// Package amqp implements tracing of messages published and consumed with
+// streadway/amqp. Trace context is propagated in headers of messages and
+// consumer spans follow from publisher spans.
+package amqp
+
+import (
+	"context"
+
+	"github.com/foodiefm/opentracing/messaging"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// component is component tag of AMQP spans
+	component = "streadway/amqp"
+
+	tagExchange    = "amqp.exchange"
+	tagRoutingKey  = "amqp.routing_key"
+	tagQueue       = "amqp.queue"
+	tagDeliveryTag = "amqp.delivery_tag"
+)
+
+// Channel publishes messages. It is implemented by *amqp.Channel.
+type Channel interface {
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// Publisher traces messages published to channel
+type Publisher struct {
+	ch  Channel
+	cfg *config
+}
+
+// WrapChannel wraps channel, so that published messages are traced
+func WrapChannel(ch Channel, opts ...Option) *Publisher {
+	return &Publisher{ch: ch, cfg: newConfig(opts...)}
+}
+
+// Publish starts publisher span as child of span in context, injects its
+// span context to headers of message and publishes message. Headers of
+// given message are not modified.
+func (p *Publisher) Publish(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+
+	span, _ := messaging.StartProducerSpan(ctx, p.cfg.publishName, destination(exchange, key), messaging.TableCarrier(headers),
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: tagExchange, Value: exchange},
+		opentracing.Tag{Key: tagRoutingKey, Value: key})
+
+	err := p.ch.Publish(exchange, key, mandatory, immediate, msg)
+	finishSpan(span, err)
+	return err
+}
+
+// Handler handles delivery with context containing consumer span
+type Handler func(ctx context.Context, d amqp.Delivery) error
+
+// HandleDeliveries handles deliveries consumed from given queue until
+// delivery channel is closed. Consumer span of delivery is finished,
+// when handler returns, and it is marked as error, if handler fails.
+// Handler is responsible for acknowledging deliveries.
+func HandleDeliveries(ctx context.Context, queue string, deliveries <-chan amqp.Delivery, h Handler, opts ...Option) {
+	cfg := newConfig(opts...)
+	for d := range deliveries {
+		span, dctx := cfg.startConsumerSpan(ctx, queue, d)
+		finishSpan(span, h(dctx, d))
+	}
+}
+
+// StartConsumerSpan starts consumer span for delivery consumed from given
+// queue. It can be used, when deliveries are not handled with HandleDeliveries.
+func StartConsumerSpan(ctx context.Context, queue string, d amqp.Delivery, opts ...Option) (opentracing.Span, context.Context) {
+	return newConfig(opts...).startConsumerSpan(ctx, queue, d)
+}
+
+func (cfg *config) startConsumerSpan(ctx context.Context, queue string, d amqp.Delivery) (opentracing.Span, context.Context) {
+	return messaging.StartConsumerSpan(ctx, cfg.consumeName, queue, messaging.TableCarrier(d.Headers),
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: tagExchange, Value: d.Exchange},
+		opentracing.Tag{Key: tagRoutingKey, Value: d.RoutingKey},
+		opentracing.Tag{Key: tagQueue, Value: queue},
+		opentracing.Tag{Key: tagDeliveryTag, Value: d.DeliveryTag})
+}
+
+// destination returns exchange or routing key, when message is
+// published to default exchange, which routes it to queue by key
+func destination(exchange, key string) string {
+	if exchange == "" {
+		return key
+	}
+	return exchange
+}
+
+// finishSpan marks span as error, if operation failed, and finishes it
+func finishSpan(span opentracing.Span, err error) {
+	if err != nil {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+	span.Finish()
+}
@@ -0,0 +1,110 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/streadway/amqp"
+)
+
+// fakeChannel delivers published messages to deliveries channel
+type fakeChannel struct {
+	deliveries chan amqp.Delivery
+	err        error
+}
+
+func (c *fakeChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.deliveries <- amqp.Delivery{
+		Headers:     msg.Headers,
+		Exchange:    exchange,
+		RoutingKey:  key,
+		DeliveryTag: uint64(len(c.deliveries) + 1),
+		Body:        msg.Body,
+	}
+	return nil
+}
+
+func TestPublishAndHandle(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	ch := &fakeChannel{deliveries: make(chan amqp.Delivery, 2)}
+	p := WrapChannel(ch)
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	msg := amqp.Publishing{Headers: amqp.Table{"retries": int32(1)}, Body: []byte("welcome")}
+	if err := p.Publish(ctx, "notifications", "email.welcome", false, false, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Publish(ctx, "", "sms", false, false, amqp.Publishing{Body: []byte("code")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root.Finish()
+	close(ch.deliveries)
+	if len(msg.Headers) != 1 {
+		t.Errorf("headers of given message modified")
+	}
+
+	handled := 0
+	HandleDeliveries(context.Background(), "emails", ch.deliveries, func(ctx context.Context, d amqp.Delivery) error {
+		if opentracing.SpanFromContext(ctx) == nil {
+			t.Errorf("consumer span not in context")
+		}
+		handled++
+		if d.RoutingKey == "sms" {
+			return errors.New("sms gateway down")
+		}
+		return nil
+	}, WithConsumeName("notification.handle"))
+	if handled != 2 {
+		t.Fatalf("incorrect number of handled deliveries: %d", handled)
+	}
+
+	publishers := recorder.FindSpans("amqp.publish")
+	consumers := recorder.FindSpans("notification.handle")
+	if len(publishers) != 2 || len(consumers) != 2 {
+		t.Fatalf("incorrect spans:\n%s", recorder.Tree())
+	}
+	tracetest.AssertTags(t, publishers[0], map[string]interface{}{
+		"span.kind":               "producer",
+		"message_bus.destination": "notifications",
+		"amqp.exchange":           "notifications",
+		"amqp.routing_key":        "email.welcome",
+	})
+	tracetest.AssertTags(t, publishers[1], map[string]interface{}{
+		"message_bus.destination": "sms",
+	})
+	tracetest.AssertTags(t, consumers[0], map[string]interface{}{
+		"span.kind":               "consumer",
+		"message_bus.destination": "emails",
+		"amqp.queue":              "emails",
+		"amqp.exchange":           "notifications",
+		"amqp.delivery_tag":       1,
+	})
+	for i := range publishers {
+		tracetest.AssertChildOf(t, publishers[i], recorder.RequireSpan(t, "root"))
+		tracetest.AssertChildOf(t, consumers[i], publishers[i])
+	}
+	tracetest.AssertError(t, consumers[0], false)
+	tracetest.AssertError(t, consumers[1], true)
+}
+
+func TestPublishError(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	p := WrapChannel(&fakeChannel{err: amqp.ErrClosed}, WithPublishName("notification.send"))
+
+	if err := p.Publish(context.Background(), "notifications", "email", false, false, amqp.Publishing{}); err != amqp.ErrClosed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tracetest.AssertError(t, recorder.RequireSpan(t, "notification.send"), true)
+}
+
+func TestChannelInterface(t *testing.T) {
+	var _ Channel = &amqp.Channel{}
+}
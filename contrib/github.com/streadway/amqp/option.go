@@ -0,0 +1,41 @@
+package amqp
+
+const (
+	defaultPublishName = "amqp.publish"
+	defaultConsumeName = "amqp.consume"
+)
+
+// config holds optional settings of publishers and delivery handlers
+type config struct {
+	publishName string
+	consumeName string
+}
+
+// Option typed functions can be used to configure
+// publishers and delivery handlers
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		publishName: defaultPublishName,
+		consumeName: defaultConsumeName,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithPublishName gives operation name of spans of published messages
+func WithPublishName(name string) Option {
+	return func(cfg *config) {
+		cfg.publishName = name
+	}
+}
+
+// WithConsumeName gives operation name of spans of handled deliveries
+func WithConsumeName(name string) Option {
+	return func(cfg *config) {
+		cfg.consumeName = name
+	}
+}
@@ -0,0 +1,174 @@
+// Package otel implements opentracing.Tracer backed by OpenTelemetry
+// tracer provider, so that middlewares and clients of this repository
+// can be used in services instrumented with OpenTelemetry.
+//
+// Package is own module, as OpenTelemetry requires newer Go version
+// than rest of the repository.
+package otel
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is name of OpenTelemetry tracer used by bridge
+const instrumentationName = "github.com/foodiefm/opentracing/contrib/go.opentelemetry.io/otel"
+
+// tagKeys maps OpenTracing tags to OpenTelemetry semantic conventions.
+// Integrations of this repository set request path as http.url,
+// so it is mapped to url.path instead of url.full.
+var tagKeys = map[string]string{
+	string(ext.HTTPMethod):            string(semconv.HTTPRequestMethodKey),
+	string(ext.HTTPUrl):               string(semconv.URLPathKey),
+	string(ext.HTTPStatusCode):        string(semconv.HTTPResponseStatusCodeKey),
+	string(ext.PeerHostname):          string(semconv.ServerAddressKey),
+	string(ext.PeerPort):              string(semconv.ServerPortKey),
+	string(ext.PeerHostIPv4):          string(semconv.NetworkPeerAddressKey),
+	string(ext.PeerHostIPv6):          string(semconv.NetworkPeerAddressKey),
+	string(ext.DBType):                string(semconv.DBSystemKey),
+	string(ext.DBInstance):            string(semconv.DBNamespaceKey),
+	string(ext.DBStatement):           string(semconv.DBQueryTextKey),
+	string(ext.MessageBusDestination): string(semconv.MessagingDestinationNameKey),
+}
+
+// Tracer is opentracing.Tracer, which starts OpenTelemetry spans
+type Tracer struct {
+	bridge  *otbridge.BridgeTracer
+	tagKeys map[string]string
+}
+
+// Option typed functions can be used to configure bridge tracer
+type Option func(*config)
+
+type config struct {
+	propagator propagation.TextMapPropagator
+	tagKeys    map[string]string
+}
+
+// WithPropagator gives propagator used to inject and extract span
+// contexts. W3C trace context and baggage are used by default.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(cfg *config) {
+		cfg.propagator = p
+	}
+}
+
+// WithTagKeys gives additional mappings from OpenTracing tags to
+// OpenTelemetry attributes. Mappings override default mappings.
+func WithTagKeys(keys map[string]string) Option {
+	return func(cfg *config) {
+		for k, v := range keys {
+			cfg.tagKeys[k] = v
+		}
+	}
+}
+
+// NewTracer creates opentracing.Tracer, which starts spans with tracer of
+// given provider. Returned provider should be used by OpenTelemetry
+// instrumentation of service, so that spans started with both APIs
+// are in same traces.
+func NewTracer(tp trace.TracerProvider, opts ...Option) (*Tracer, trace.TracerProvider) {
+	cfg := &config{
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		tagKeys:    map[string]string{},
+	}
+	for k, v := range tagKeys {
+		cfg.tagKeys[k] = v
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	bridge, provider := otbridge.NewTracerPair(tp.Tracer(instrumentationName))
+	bridge.SetTextMapPropagator(cfg.propagator)
+	bridge.SetWarningHandler(func(string) {})
+
+	return &Tracer{
+		bridge:  bridge,
+		tagKeys: cfg.tagKeys,
+	}, provider
+}
+
+// tagKey returns OpenTelemetry attribute key for OpenTracing tag
+func (t *Tracer) tagKey(key string) string {
+	if k, ok := t.tagKeys[key]; ok {
+		return k
+	}
+	return key
+}
+
+// StartSpan starts OpenTelemetry span with tags mapped to semantic conventions
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	sso := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&sso)
+	}
+
+	bopts := []opentracing.StartSpanOption{}
+	if !sso.StartTime.IsZero() {
+		bopts = append(bopts, opentracing.StartTime(sso.StartTime))
+	}
+	tags := opentracing.Tags{}
+	for k, v := range sso.Tags {
+		tags[t.tagKey(k)] = v
+	}
+	bopts = append(bopts, tags)
+	for _, ref := range sso.References {
+		bopts = append(bopts, ref)
+	}
+
+	return &span{
+		Span:   t.bridge.StartSpan(operationName, bopts...),
+		tracer: t,
+	}
+}
+
+// Inject injects span context using propagator of tracer
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	return t.bridge.Inject(sm, format, carrier)
+}
+
+// Extract extracts span context using propagator of tracer
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	return t.bridge.Extract(format, carrier)
+}
+
+// ContextWithSpanHook makes span active also for OpenTelemetry API,
+// when it is added to context with opentracing.ContextWithSpan
+func (t *Tracer) ContextWithSpanHook(ctx context.Context, s opentracing.Span) context.Context {
+	if bs, ok := s.(*span); ok {
+		return t.bridge.ContextWithSpanHook(ctx, bs.Span)
+	}
+	return ctx
+}
+
+// span maps tags set after span is started
+type span struct {
+	opentracing.Span
+	tracer *Tracer
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.Span.SetTag(s.tracer.tagKey(key), value)
+	return s
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.Span.SetOperationName(operationName)
+	return s
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.Span.SetBaggageItem(restrictedKey, value)
+	return s
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
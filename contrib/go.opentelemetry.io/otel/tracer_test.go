@@ -0,0 +1,130 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTestTracer(opts ...Option) (*Tracer, trace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer, provider := NewTracer(tp, opts...)
+	return tracer, provider, recorder
+}
+
+func attributes(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracerStartSpan(t *testing.T) {
+	tracer, _, recorder := newTestTracer(WithTagKeys(map[string]string{"server.errors": "error.message"}))
+
+	root := tracer.StartSpan("GET__test", ext.SpanKindRPCServer, opentracing.Tag{Key: "http.method", Value: "GET"})
+	ext.HTTPUrl.Set(root, "/test")
+	ext.HTTPStatusCode.Set(root, uint16(500))
+	root.SetTag("server.errors", "fatal error")
+	ext.Error.Set(root, true)
+	root.SetTag("component", "gin")
+
+	child := tracer.StartSpan("http.request", opentracing.ChildOf(root.Context()), ext.SpanKindRPCClient)
+	ext.PeerHostname.Set(child, "payments")
+	child.Finish()
+	root.Finish()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("incorrect number of spans: %d", len(spans))
+	}
+	childSpan, rootSpan := spans[0], spans[1]
+
+	if rootSpan.SpanKind() != trace.SpanKindServer || childSpan.SpanKind() != trace.SpanKindClient {
+		t.Errorf("incorrect span kinds")
+	}
+	if childSpan.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+		t.Errorf("child span is not child of root")
+	}
+	if rootSpan.Status().Code != codes.Error {
+		t.Errorf("error tag not mapped to status")
+	}
+
+	attrs := attributes(rootSpan)
+	want := map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue("GET"),
+		"url.path":                  attribute.StringValue("/test"),
+		"http.response.status_code": attribute.Int64Value(500),
+		"error.message":             attribute.StringValue("fatal error"),
+		"component":                 attribute.StringValue("gin"),
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("attribute %s = %v, want %v", k, attrs[k].Emit(), v.Emit())
+		}
+	}
+	if v := attributes(childSpan)["server.address"]; v != attribute.StringValue("payments") {
+		t.Errorf("peer hostname not mapped: %v", v.Emit())
+	}
+}
+
+func TestTracerInjectExtract(t *testing.T) {
+	tracer, _, _ := newTestTracer()
+
+	span := tracer.StartSpan("root")
+	span.SetBaggageItem("tenant_id", "42")
+	h := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		t.Fatalf("unexpected inject error: %v", err)
+	}
+	if h.Get("traceparent") == "" {
+		t.Errorf("trace context not injected")
+	}
+
+	sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatalf("unexpected extract error: %v", err)
+	}
+	baggage := ""
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		if k == "tenant_id" {
+			baggage = v
+		}
+		return true
+	})
+	if baggage != "42" {
+		t.Errorf("baggage not propagated")
+	}
+
+	if _, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{})); err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected span context not found, got %v", err)
+	}
+}
+
+func TestTracerContextWithSpan(t *testing.T) {
+	tracer, provider, recorder := newTestTracer()
+
+	span := tracer.StartSpan("opentracing")
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+	_, otelSpan := provider.Tracer("test").Start(ctx, "opentelemetry")
+	otelSpan.End()
+	span.Finish()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("incorrect number of spans: %d", len(spans))
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("OpenTelemetry span is not child of OpenTracing span")
+	}
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor traces unary calls. Like wrapped http clients,
+// interceptor starts span only if context of call contains span.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts...)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		span, ctx := cfg.startClientSpan(ctx, cc, method)
+		if span == nil {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		cfg.finishSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor traces streaming calls. Span is finished, when
+// stream ends with error or io.EOF, or when context of call is done.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		span, ctx := cfg.startClientSpan(ctx, cc, method)
+		if span == nil {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			cfg.finishSpan(span, err)
+			return nil, err
+		}
+
+		stream := &clientStream{
+			ClientStream: cs,
+			desc:         desc,
+			done:         make(chan struct{}),
+		}
+		stream.finish = func(err error) {
+			stream.once.Do(func() {
+				close(stream.done)
+				cfg.finishSpan(span, err)
+			})
+		}
+		go func() {
+			select {
+			case <-stream.done:
+			case <-ctx.Done():
+				stream.finish(status.FromContextError(ctx.Err()).Err())
+			}
+		}()
+		return stream, nil
+	}
+}
+
+// startClientSpan starts span for outgoing call and injects its context
+// to outgoing metadata. Nil span is returned, if context has no span.
+func (cfg *config) startClientSpan(ctx context.Context, cc *grpc.ClientConn, method string) (opentracing.Span, context.Context) {
+	if opentracing.SpanFromContext(ctx) == nil {
+		return nil, ctx
+	}
+
+	opts := append(startSpanOptions(method), ext.SpanKindRPCClient)
+	if cfg.peerService != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerService), Value: cfg.peerService})
+	}
+	span, ctx := utils.StartSpanFromContext(ctx, method, opts...)
+	ext.PeerAddress.Set(span, cc.Target())
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, metadataCarrier(md))
+	return span, metadata.NewOutgoingContext(ctx, md)
+}
+
+// clientStream finishes span, when stream ends
+type clientStream struct {
+	grpc.ClientStream
+	desc   *grpc.StreamDesc
+	done   chan struct{}
+	once   sync.Once
+	finish func(error)
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.finish(err)
+	}
+	return md, err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+// RecvMsg finishes span on io.EOF or error. Calls without server
+// streaming have only one response, so span is finished after it.
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.finish(nil)
+	case err != nil:
+		s.finish(err)
+	case !s.desc.ServerStreams:
+		s.finish(nil)
+	}
+	return err
+}
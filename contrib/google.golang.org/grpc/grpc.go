@@ -0,0 +1,42 @@
+// Package grpc implements client and server interceptors, which trace
+// gRPC calls using opentracing. Span context is propagated in metadata
+// of calls and spans are named by full method of called service.
+package grpc
+
+import (
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc/status"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+const (
+	// component is component tag of gRPC spans
+	component = "grpc"
+	// tagCode is tag for status code of call
+	tagCode = "grpc.code"
+	// tagMethod is tag for full method of call
+	tagMethod = "grpc.method"
+)
+
+// startSpanOptions returns options common to client and server spans
+func startSpanOptions(method string) []opentracing.StartSpanOption {
+	return []opentracing.StartSpanOption{
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracer.SpanTypeOption(ddext.AppTypeRPC),
+		opentracing.Tag{Key: tagMethod, Value: method},
+	}
+}
+
+// finishSpan adds status code of call to span and finishes it.
+// Span is marked as error, if status code is classified as error.
+func (cfg *config) finishSpan(span opentracing.Span, err error) {
+	code := status.Code(err)
+	span.SetTag(tagCode, code.String())
+	if err != nil && cfg.errorCodes[code] {
+		span.SetTag("server.errors", err.Error())
+		ext.Error.Set(span, true)
+	}
+	span.Finish()
+}
@@ -0,0 +1,209 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial starts health server with interceptors in memory and
+// returns client connection to it
+func dial(t *testing.T, opts ...Option) (*grpc.ClientConn, func()) {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(opts...)),
+	)
+	hs := health.NewServer()
+	hs.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, hs)
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(opts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(opts...)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+// waitSpans waits until recorder has given number of finished spans
+func waitSpans(r *tracetest.Recorder, count int) {
+	for i := 0; i < 100 && len(r.FinishedSpans()) < count; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		opts    []Option
+		root    bool
+		spans   int
+		code    codes.Code
+		err     bool
+	}{
+		{"no root span", "orders", nil, false, 1, codes.OK, false},
+		{"root span", "orders", nil, true, 3, codes.OK, false},
+		{"client error", "unknown", nil, true, 3, codes.NotFound, false},
+		{"client error as error", "unknown", []Option{WithErrorCodes(codes.NotFound)}, true, 3, codes.NotFound, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder, done := tracetest.Global()
+			defer done()
+			conn, stop := dial(t, append(test.opts, WithPeerService("orders-api"))...)
+			defer stop()
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "1")
+			var root opentracing.Span
+			if test.root {
+				root, ctx = opentracing.StartSpanFromContext(ctx, "root")
+			}
+			_, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: test.service})
+			if status.Code(err) != test.code {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if root != nil {
+				root.Finish()
+			}
+
+			waitSpans(recorder, test.spans)
+			if !recorder.AssertSpanCount(t, test.spans) {
+				return
+			}
+			server := recorder.FindSpansByTag("span.kind", "server")[0]
+			tracetest.AssertTags(t, server, map[string]interface{}{
+				"component":   "grpc",
+				"grpc.method": "/grpc.health.v1.Health/Check",
+				"grpc.code":   test.code.String(),
+			})
+			tracetest.AssertError(t, server, test.err)
+			if server.OperationName != "/grpc.health.v1.Health/Check" {
+				t.Errorf("incorrect operation name: %s", server.OperationName)
+			}
+			if server.Tag("peer.address") == nil {
+				t.Errorf("peer address not tagged")
+			}
+			if !test.root {
+				return
+			}
+
+			client := recorder.FindSpansByTag("span.kind", "client")[0]
+			tracetest.AssertTags(t, client, map[string]interface{}{
+				"peer.address": "bufnet",
+				"peer.service": "orders-api",
+				"grpc.code":    test.code.String(),
+			})
+			tracetest.AssertError(t, client, test.err)
+			tracetest.AssertChildOf(t, server, client)
+			tracetest.AssertChildOf(t, client, recorder.RequireSpan(t, "root"))
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+	conn, stop := dial(t)
+	defer stop()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{Service: "orders"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("unexpected receive error: %v", err)
+	}
+	cancel()
+	root.Finish()
+
+	waitSpans(recorder, 3)
+	if !recorder.AssertSpanCount(t, 3) {
+		return
+	}
+	client := recorder.FindSpansByTag("span.kind", "client")[0]
+	server := recorder.FindSpansByTag("span.kind", "server")[0]
+	tracetest.AssertTags(t, client, map[string]interface{}{
+		"grpc.method": "/grpc.health.v1.Health/Watch",
+		"grpc.code":   codes.Canceled.String(),
+	})
+	tracetest.AssertError(t, client, false)
+	tracetest.AssertChildOf(t, server, client)
+}
+
+func TestClientStreamFinish(t *testing.T) {
+	tests := []struct {
+		name     string
+		streams  bool
+		err      error
+		finished bool
+		code     string
+	}{
+		{"message of server stream", true, nil, false, ""},
+		{"single response", false, nil, true, "OK"},
+		{"end of stream", true, io.EOF, true, "OK"},
+		{"stream error", true, status.Error(codes.Internal, "failure"), true, "Internal"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracer := mocktracer.New()
+			span := tracer.StartSpan("stream")
+			cfg := newConfig()
+			stream := &clientStream{
+				ClientStream: fakeClientStream{err: test.err},
+				desc:         &grpc.StreamDesc{ServerStreams: test.streams},
+				done:         make(chan struct{}),
+			}
+			stream.finish = func(err error) {
+				stream.once.Do(func() { cfg.finishSpan(span, err) })
+			}
+			stream.RecvMsg(nil)
+
+			spans := tracer.FinishedSpans()
+			if (len(spans) == 1) != test.finished {
+				t.Fatalf("span finished = %v, expected: %v", len(spans) == 1, test.finished)
+			}
+			if test.finished && spans[0].Tag("grpc.code") != test.code {
+				t.Errorf("incorrect code: %v", spans[0].Tag("grpc.code"))
+			}
+		})
+	}
+}
+
+// fakeClientStream returns given error from RecvMsg
+type fakeClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s fakeClientStream) RecvMsg(m interface{}) error {
+	return s.err
+}
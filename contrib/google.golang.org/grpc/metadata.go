@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier implements opentracing.TextMapWriter and
+// opentracing.TextMapReader for gRPC metadata
+type metadataCarrier metadata.MD
+
+// Set sets key in lowercase, as gRPC metadata keys are case insensitive
+func (c metadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+// ForeachKey calls handler for every value of metadata
+func (c metadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c {
+		for _, v := range vs {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
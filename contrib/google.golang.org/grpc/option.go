@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// serverErrorCodes are status codes, which are classified as
+// errors by default. Other codes are caused by caller.
+var serverErrorCodes = []codes.Code{
+	codes.Unknown,
+	codes.DeadlineExceeded,
+	codes.Unimplemented,
+	codes.Internal,
+	codes.Unavailable,
+	codes.DataLoss,
+}
+
+// config holds optional settings of interceptors
+type config struct {
+	peerService string
+	errorCodes  map[codes.Code]bool
+}
+
+// Option typed functions can be used to configure interceptors
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{}
+	WithErrorCodes(serverErrorCodes...)(cfg)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithPeerService sets name of the called service
+// to spans of outgoing calls
+func WithPeerService(name string) Option {
+	return func(cfg *config) {
+		cfg.peerService = name
+	}
+}
+
+// WithErrorCodes gives status codes, which mark spans as errors.
+// By default server side errors Unknown, DeadlineExceeded,
+// Unimplemented, Internal, Unavailable and DataLoss are errors.
+func WithErrorCodes(errorCodes ...codes.Code) Option {
+	return func(cfg *config) {
+		cfg.errorCodes = map[codes.Code]bool{}
+		for _, code := range errorCodes {
+			cfg.errorCodes[code] = true
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryServerInterceptor traces incoming unary calls. Span context of
+// caller is extracted from metadata, otherwise new root span is started.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span, ctx := startServerSpan(ctx, info.FullMethod)
+		res, err := handler(ctx, req)
+		cfg.finishSpan(span, err)
+		return res, err
+	}
+}
+
+// StreamServerInterceptor traces incoming streaming calls.
+// Span is finished, when handler of stream returns.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		span, ctx := startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		cfg.finishSpan(span, err)
+		return err
+	}
+}
+
+// startServerSpan starts span for incoming call as child of
+// span context in incoming metadata
+func startServerSpan(ctx context.Context, method string) (opentracing.Span, context.Context) {
+	var wireContext opentracing.SpanContext
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		wireContext, _ = opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, metadataCarrier(md))
+	}
+
+	opts := append(startSpanOptions(method), ext.RPCServerOption(wireContext))
+	span, ctx := utils.StartSpanFromContext(ctx, method, opts...)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ext.PeerAddress.Set(span, p.Addr.String())
+	}
+	return span, ctx
+}
+
+// serverStream gives context with span to stream handler
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
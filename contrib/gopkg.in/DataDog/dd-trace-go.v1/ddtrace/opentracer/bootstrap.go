@@ -0,0 +1,130 @@
+package opentracer
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/opentracing/opentracing-go"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// defaultAgentPort is port of Datadog agent, if it is not given
+const defaultAgentPort = "8126"
+
+// BootstrapConfig holds settings used to start tracer with Bootstrap
+type BootstrapConfig struct {
+	// Service is name of service, read from DD_SERVICE
+	Service string
+	// Env is environment of service, read from DD_ENV
+	Env string
+	// Version is version of service, read from DD_VERSION
+	Version string
+	// AgentHost is host of Datadog agent, read from DD_AGENT_HOST
+	AgentHost string
+	// AgentPort is port of Datadog agent, read from DD_TRACE_AGENT_PORT
+	AgentPort string
+	// SampleRate is rate of sampled traces, read from DD_TRACE_SAMPLE_RATE.
+	// NaN means that all traces are sampled.
+	SampleRate float64
+	// AnalyticsRate is rate of Trace Search & Analytics events, read from
+	// DD_TRACE_ANALYTICS_RATE. NaN means that analytics is not enabled.
+	AnalyticsRate float64
+	// Enabled tells if traces are sent to agent, read from DD_TRACE_ENABLED.
+	// Tracing is enabled by default.
+	Enabled bool
+	// Options are used to configure wrapped tracer
+	Options []Option
+}
+
+// ConfigFromEnv reads tracer settings from environment variables
+func ConfigFromEnv() (BootstrapConfig, error) {
+	cfg := BootstrapConfig{
+		Service:       os.Getenv("DD_SERVICE"),
+		Env:           os.Getenv("DD_ENV"),
+		Version:       os.Getenv("DD_VERSION"),
+		AgentHost:     os.Getenv("DD_AGENT_HOST"),
+		AgentPort:     os.Getenv("DD_TRACE_AGENT_PORT"),
+		SampleRate:    math.NaN(),
+		AnalyticsRate: math.NaN(),
+		Enabled:       true,
+	}
+
+	var err error
+	if v := os.Getenv("DD_TRACE_SAMPLE_RATE"); v != "" {
+		if cfg.SampleRate, err = strconv.ParseFloat(v, 64); err != nil {
+			return cfg, fmt.Errorf("invalid DD_TRACE_SAMPLE_RATE: %v", err)
+		}
+	}
+	if v := os.Getenv("DD_TRACE_ANALYTICS_RATE"); v != "" {
+		if cfg.AnalyticsRate, err = strconv.ParseFloat(v, 64); err != nil {
+			return cfg, fmt.Errorf("invalid DD_TRACE_ANALYTICS_RATE: %v", err)
+		}
+	}
+	if v := os.Getenv("DD_TRACE_ENABLED"); v != "" {
+		if cfg.Enabled, err = strconv.ParseBool(v); err != nil {
+			return cfg, fmt.Errorf("invalid DD_TRACE_ENABLED: %v", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// startOptions returns options to start DataDog tracer with
+func (cfg BootstrapConfig) startOptions() []ddtracer.StartOption {
+	opts := []ddtracer.StartOption{}
+	if cfg.Env != "" {
+		opts = append(opts, ddtracer.WithGlobalTag("env", cfg.Env))
+	}
+	if cfg.Version != "" {
+		opts = append(opts, ddtracer.WithGlobalTag("version", cfg.Version))
+	}
+	if cfg.AgentHost != "" || cfg.AgentPort != "" {
+		host, port := cfg.AgentHost, cfg.AgentPort
+		if host == "" {
+			host = "localhost"
+		}
+		if port == "" {
+			port = defaultAgentPort
+		}
+		opts = append(opts, ddtracer.WithAgentAddr(net.JoinHostPort(host, port)))
+	}
+	if !math.IsNaN(cfg.SampleRate) {
+		opts = append(opts, ddtracer.WithSampler(ddtracer.NewRateSampler(cfg.SampleRate)))
+	}
+	if !math.IsNaN(cfg.AnalyticsRate) {
+		opts = append(opts, ddtracer.WithAnalyticsRate(cfg.AnalyticsRate))
+	}
+	return opts
+}
+
+// Bootstrap reads tracer settings from environment, applies given overrides
+// to them and sets started tracer as global tracer. Returned function
+// flushes and stops tracer, and it should be called when service shuts down.
+// If tracing is not enabled, global tracer is not changed.
+func Bootstrap(overrides ...func(*BootstrapConfig)) (func(), error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		override(&cfg)
+	}
+	if !cfg.Enabled {
+		return func() {}, nil
+	}
+	if cfg.Service == "" {
+		return nil, fmt.Errorf("service name is not given")
+	}
+
+	opts := append([]Option{WithStartOptions(cfg.startOptions()...)}, cfg.Options...)
+	t := NewTracer(cfg.Service, opts...)
+	opentracing.SetGlobalTracer(t)
+
+	return func() {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		t.Close()
+	}, nil
+}
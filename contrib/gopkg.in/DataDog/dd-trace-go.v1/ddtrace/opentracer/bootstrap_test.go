@@ -0,0 +1,149 @@
+package opentracer
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// setEnv sets environment variables and returns function to restore them
+func setEnv(env map[string]string) func() {
+	restore := []func(){}
+	for k, v := range env {
+		k := k
+		old, exists := os.LookupEnv(k)
+		os.Setenv(k, v)
+		restore = append(restore, func() {
+			if exists {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+	return func() {
+		for _, r := range restore {
+			r()
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    BootstrapConfig
+		wantErr bool
+	}{
+		{"empty environment", map[string]string{}, BootstrapConfig{
+			SampleRate:    math.NaN(),
+			AnalyticsRate: math.NaN(),
+			Enabled:       true,
+		}, false},
+		{"full environment", map[string]string{
+			"DD_SERVICE":              "orders",
+			"DD_ENV":                  "staging",
+			"DD_VERSION":              "1.2.3",
+			"DD_AGENT_HOST":           "datadog",
+			"DD_TRACE_AGENT_PORT":     "8127",
+			"DD_TRACE_SAMPLE_RATE":    "0.5",
+			"DD_TRACE_ANALYTICS_RATE": "0.1",
+			"DD_TRACE_ENABLED":        "false",
+		}, BootstrapConfig{
+			Service:       "orders",
+			Env:           "staging",
+			Version:       "1.2.3",
+			AgentHost:     "datadog",
+			AgentPort:     "8127",
+			SampleRate:    0.5,
+			AnalyticsRate: 0.1,
+			Enabled:       false,
+		}, false},
+		{"invalid sample rate", map[string]string{"DD_TRACE_SAMPLE_RATE": "half"}, BootstrapConfig{}, true},
+		{"invalid analytics rate", map[string]string{"DD_TRACE_ANALYTICS_RATE": "all"}, BootstrapConfig{}, true},
+		{"invalid enabled flag", map[string]string{"DD_TRACE_ENABLED": "maybe"}, BootstrapConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setEnv(map[string]string{
+				"DD_SERVICE": "", "DD_ENV": "", "DD_VERSION": "", "DD_AGENT_HOST": "", "DD_TRACE_AGENT_PORT": "",
+				"DD_TRACE_SAMPLE_RATE": "", "DD_TRACE_ANALYTICS_RATE": "", "DD_TRACE_ENABLED": "",
+			})()
+			defer setEnv(tt.env)()
+
+			got, err := ConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Service != tt.want.Service || got.Env != tt.want.Env || got.Version != tt.want.Version ||
+				got.AgentHost != tt.want.AgentHost || got.AgentPort != tt.want.AgentPort || got.Enabled != tt.want.Enabled {
+				t.Errorf("ConfigFromEnv() = %+v, want %+v", got, tt.want)
+			}
+			if !sameRate(got.SampleRate, tt.want.SampleRate) || !sameRate(got.AnalyticsRate, tt.want.AnalyticsRate) {
+				t.Errorf("ConfigFromEnv() rates = %v, %v, want %v, %v", got.SampleRate, got.AnalyticsRate, tt.want.SampleRate, tt.want.AnalyticsRate)
+			}
+		})
+	}
+}
+
+func sameRate(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestBootstrapConfigStartOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  BootstrapConfig
+		want int
+	}{
+		{"no settings", BootstrapConfig{SampleRate: math.NaN(), AnalyticsRate: math.NaN()}, 0},
+		{"all settings", BootstrapConfig{Env: "prod", Version: "1", AgentHost: "datadog", SampleRate: 1, AnalyticsRate: 0}, 5},
+		{"agent port only", BootstrapConfig{AgentPort: "8127", SampleRate: math.NaN(), AnalyticsRate: math.NaN()}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(tt.cfg.startOptions()); got != tt.want {
+				t.Errorf("startOptions() returned %d options, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	defer setEnv(map[string]string{"DD_SERVICE": "", "DD_TRACE_ENABLED": ""})()
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	if _, err := Bootstrap(); err == nil {
+		t.Errorf("expected error without service name")
+	}
+
+	closer, err := Bootstrap(func(cfg *BootstrapConfig) {
+		cfg.Enabled = false
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	closer()
+	if _, ok := opentracing.GlobalTracer().(*opentracer); ok {
+		t.Errorf("tracer set even if tracing is disabled")
+	}
+
+	closer, err = Bootstrap(func(cfg *BootstrapConfig) {
+		cfg.Service = "orders"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := opentracing.GlobalTracer().(*opentracer); !ok {
+		t.Errorf("tracer not set as global tracer")
+	}
+	closer()
+	if _, ok := opentracing.GlobalTracer().(opentracing.NoopTracer); !ok {
+		t.Errorf("global tracer not reset when closed")
+	}
+}
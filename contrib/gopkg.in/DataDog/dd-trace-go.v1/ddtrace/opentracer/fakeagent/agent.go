@@ -0,0 +1,126 @@
+// Package fakeagent implements in-process Datadog agent, which decodes
+// traces sent by Datadog tracer, so that tests can assert on spans
+// exactly as they would be received by real agent.
+package fakeagent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// tracesPath is path of trace endpoint used by Datadog tracer
+const tracesPath = "/v0.4/traces"
+
+// Span is span as it is sent to Datadog agent
+type Span struct {
+	Name     string             `json:"name"`
+	Service  string             `json:"service"`
+	Resource string             `json:"resource"`
+	Type     string             `json:"type"`
+	Start    int64              `json:"start"`
+	Duration int64              `json:"duration"`
+	Meta     map[string]string  `json:"meta"`
+	Metrics  map[string]float64 `json:"metrics"`
+	SpanID   uint64             `json:"span_id"`
+	TraceID  uint64             `json:"trace_id"`
+	ParentID uint64             `json:"parent_id"`
+	Error    int32              `json:"error"`
+}
+
+// Agent is fake Datadog agent running in test http server.
+// It is safe for concurrent use.
+type Agent struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	traces [][]Span
+}
+
+// New starts fake agent. Agent must be closed when test ends.
+func New() *Agent {
+	a := &Agent{}
+	a.server = httptest.NewServer(http.HandlerFunc(a.serveHTTP))
+	return a
+}
+
+// Addr returns address of agent, which can be given
+// to Datadog tracer with tracer.WithAgentAddr
+func (a *Agent) Addr() string {
+	return strings.TrimPrefix(a.server.URL, "http://")
+}
+
+// Close shuts down agent
+func (a *Agent) Close() {
+	a.server.Close()
+}
+
+// Traces returns traces received by agent
+func (a *Agent) Traces() [][]Span {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	traces := make([][]Span, len(a.traces))
+	copy(traces, a.traces)
+	return traces
+}
+
+// Spans returns spans of all traces received by agent
+func (a *Agent) Spans() []Span {
+	spans := []Span{}
+	for _, trace := range a.Traces() {
+		spans = append(spans, trace...)
+	}
+	return spans
+}
+
+// FindSpan returns first received span with given
+// operation name or nil, if there is no such span
+func (a *Agent) FindSpan(name string) *Span {
+	for _, span := range a.Spans() {
+		if span.Name == name {
+			return &span
+		}
+	}
+	return nil
+}
+
+// Reset removes received traces
+func (a *Agent) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.traces = nil
+}
+
+// serveHTTP decodes traces sent to trace endpoint.
+// Requests to other endpoints are accepted and ignored.
+func (a *Agent) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != tracesPath {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// payload is decoded through JSON, so that
+	// span fields can be read with struct tags
+	b := &bytes.Buffer{}
+	if _, err := msgp.CopyToJSON(b, r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	traces := [][]Span{}
+	if err := json.Unmarshal(b.Bytes(), &traces); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	a.traces = append(a.traces, traces...)
+	a.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"rate_by_service":{}}`))
+}
@@ -0,0 +1,60 @@
+package fakeagent
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+func post(t *testing.T, a *Agent, path string, body []byte) int {
+	t.Helper()
+	res, err := http.Post("http://"+a.Addr()+path, "application/msgpack", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestAgent(t *testing.T) {
+	a := New()
+	defer a.Close()
+
+	var b []byte
+	b = msgp.AppendArrayHeader(b, 1)
+	b = msgp.AppendArrayHeader(b, 1)
+	b = msgp.AppendMapHeader(b, 4)
+	b = msgp.AppendString(b, "name")
+	b = msgp.AppendString(b, "http.request")
+	b = msgp.AppendString(b, "span_id")
+	b = msgp.AppendUint64(b, 1<<63)
+	b = msgp.AppendString(b, "meta")
+	b = msgp.AppendMapStrStr(b, map[string]string{"span.kind": "client"})
+	b = msgp.AppendString(b, "error")
+	b = msgp.AppendInt32(b, 1)
+
+	if code := post(t, a, tracesPath, b); code != http.StatusOK {
+		t.Fatalf("incorrect status code: %d", code)
+	}
+	span := a.FindSpan("http.request")
+	if span == nil {
+		t.Fatalf("span not received")
+	}
+	if span.SpanID != 1<<63 || span.Meta["span.kind"] != "client" || span.Error != 1 {
+		t.Errorf("incorrectly decoded span: %+v", span)
+	}
+
+	if code := post(t, a, tracesPath, []byte("invalid")); code != http.StatusBadRequest {
+		t.Errorf("invalid payload accepted: %d", code)
+	}
+	if code := post(t, a, "/v0.4/services", nil); code != http.StatusOK {
+		t.Errorf("incorrect status code: %d", code)
+	}
+
+	a.Reset()
+	if len(a.Traces()) != 0 {
+		t.Errorf("traces not removed on reset")
+	}
+}
@@ -0,0 +1,71 @@
+package opentracer
+
+import (
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// defaultSpanName is Datadog operation name of spans,
+// which don't have component or matching name rule
+const defaultSpanName = "http.request"
+
+// NameRule maps span kind and component tags of span to Datadog
+// operation name. Empty kind or component matches any value.
+//
+// For example rules
+//
+//	[]NameRule{
+//	    {Kind: "client", Component: "net/http", Name: "http.request"},
+//	    {Component: "db", Name: "sql.query"},
+//	}
+//
+// name outgoing http calls as http.request and database calls as sql.query.
+type NameRule struct {
+	Kind      string
+	Component string
+	Name      string
+}
+
+// matches checks if rule matches to given kind and component
+func (r NameRule) matches(kind, component string) bool {
+	return (r.Kind == "" || r.Kind == kind) &&
+		(r.Component == "" || r.Component == component)
+}
+
+// NameResolver typed functions can be used to resolve Datadog operation
+// name and resource from OpenTracing operation name and start options.
+// Empty name or resource falls back to default resolution.
+type NameResolver func(operationName string, opts opentracing.StartSpanOptions) (name, resource string)
+
+// spanName resolves Datadog operation name from span kind and component tags
+// of start options. First matching rule is used and if there is none,
+// component or default name is used as name.
+func spanName(rules []NameRule, defaultName string, sso *opentracing.StartSpanOptions) string {
+	kind := tagString(sso.Tags[string(ext.SpanKind)])
+	component := tagString(sso.Tags[string(ext.Component)])
+	for _, rule := range rules {
+		if rule.Name != "" && rule.matches(kind, component) {
+			return rule.Name
+		}
+	}
+	if component != "" {
+		return component
+	}
+	return defaultName
+}
+
+// tagString returns string presentation of tag value
+func tagString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case ext.SpanKindEnum:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,84 @@
+package opentracer
+
+import (
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+func TestSpanName(t *testing.T) {
+	rules := []NameRule{
+		{Kind: "client", Component: "net/http", Name: "http.request"},
+		{Component: "db", Name: "sql.query"},
+	}
+	tests := []struct {
+		name  string
+		rules []NameRule
+		opts  []opentracing.StartSpanOption
+		want  string
+	}{
+		{"no options", nil, nil, "http.request"},
+		{"component option", nil, []opentracing.StartSpanOption{ComponentOption("worker")}, "worker"},
+		{"empty component option", nil, []opentracing.StartSpanOption{ComponentOption("")}, "http.request"},
+		{"component tag", nil, []opentracing.StartSpanOption{opentracing.Tag{Key: "component", Value: "worker"}}, "worker"},
+		{"component and kind rule", rules, []opentracing.StartSpanOption{
+			opentracing.Tag{Key: string(ext.Component), Value: "net/http"},
+			ext.SpanKindRPCClient,
+		}, "http.request"},
+		{"component rule", rules, []opentracing.StartSpanOption{ComponentOption("db"), ext.SpanKindRPCClient}, "sql.query"},
+		{"kind not matching", rules, []opentracing.StartSpanOption{ComponentOption("net/http"), ext.SpanKindRPCServer}, "net/http"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sso := opentracing.StartSpanOptions{Tags: map[string]interface{}{}}
+			for _, opt := range tt.opts {
+				opt.Apply(&sso)
+			}
+			if got := spanName(tt.rules, defaultSpanName, &sso); got != tt.want {
+				t.Errorf("spanName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveName(t *testing.T) {
+	resolver := func(operationName string, opts opentracing.StartSpanOptions) (string, string) {
+		if opts.Tags["component"] == "worker" {
+			return "job.run", "job " + operationName
+		}
+		if opts.Tags["component"] == "consumer" {
+			return "queue.consume", ""
+		}
+		return "", ""
+	}
+	tests := []struct {
+		name         string
+		opts         []Option
+		spanOpts     []opentracing.StartSpanOption
+		wantName     string
+		wantResource string
+	}{
+		{"defaults", nil, nil, "http.request", "operation"},
+		{"default name", []Option{WithDefaultName("custom.operation")}, nil, "custom.operation", "operation"},
+		{"resolver", []Option{WithNameResolver(resolver)}, []opentracing.StartSpanOption{ComponentOption("worker")}, "job.run", "job operation"},
+		{"resolver without resource", []Option{WithNameResolver(resolver)}, []opentracing.StartSpanOption{ComponentOption("consumer")}, "queue.consume", "operation"},
+		{"resolver without result", []Option{WithNameResolver(resolver), WithDefaultName("custom.operation")}, nil, "custom.operation", "operation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &opentracer{defaultName: defaultSpanName}
+			for _, opt := range tt.opts {
+				opt(o)
+			}
+			sso := opentracing.StartSpanOptions{Tags: map[string]interface{}{}}
+			for _, opt := range tt.spanOpts {
+				opt.Apply(&sso)
+			}
+			name, resource := o.resolveName("operation", &sso)
+			if name != tt.wantName || resource != tt.wantResource {
+				t.Errorf("resolveName() = %v, %v, want %v, %v", name, resource, tt.wantName, tt.wantResource)
+			}
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package opentracer
 
 import (
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
 	dd "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
@@ -25,38 +32,214 @@ func ComponentOption(c string) opentracing.StartSpanOption {
 	return component{c}
 }
 
+// Tracer is opentracing.Tracer, which can be closed, so that traces
+// are not lost when service shuts down. DataDog tracer v1.16 can't be
+// flushed without stopping it, so Close is the only way to flush
+// buffered traces and tracer has no Flush method.
+type Tracer interface {
+	opentracing.Tracer
+	io.Closer
+}
+
 type opentracer struct {
+	mu          sync.Mutex
 	tracer      opentracing.Tracer
+	closed      bool
 	serviceName string
+	nameRules   []NameRule
+	defaultName string
+	resolver    NameResolver
+	startOpts   []ddtracer.StartOption
+}
+
+// Option typed functions can be used to configure wrapped tracer
+type Option func(*opentracer)
+
+// WithStartOptions gives options used to start DataDog tracer
+func WithStartOptions(opts ...ddtracer.StartOption) Option {
+	return func(o *opentracer) {
+		o.startOpts = append(o.startOpts, opts...)
+	}
+}
+
+// WithNameRules gives rules to map span kind and component
+// tags of spans to Datadog operation names
+func WithNameRules(rules ...NameRule) Option {
+	return func(o *opentracer) {
+		o.nameRules = append(o.nameRules, rules...)
+	}
+}
+
+// WithDefaultName gives Datadog operation name for spans,
+// which don't have component or matching name rule
+func WithDefaultName(name string) Option {
+	return func(o *opentracer) {
+		o.defaultName = name
+	}
+}
+
+// WithNameResolver gives function to resolve Datadog operation
+// name and resource, before name rules and component are used
+func WithNameResolver(resolver NameResolver) Option {
+	return func(o *opentracer) {
+		o.resolver = resolver
+	}
 }
 
 // New creates, DataDog tracer instance and wraps it so
 // that standard operation name and component definitions
 // can be used
-func New(serviceName string, opts ...ddtracer.StartOption) opentracing.Tracer {
-	opts = append(opts, tracer.WithServiceName(serviceName))
-	return &opentracer{
+func New(serviceName string, opts ...ddtracer.StartOption) Tracer {
+	return NewTracer(serviceName, WithStartOptions(opts...))
+}
+
+// NewTracer creates DataDog tracer instance like New,
+// but wrapped tracer can be configured with options
+func NewTracer(serviceName string, opts ...Option) Tracer {
+	o := &opentracer{
 		serviceName: serviceName,
-		tracer:      dd.New(opts...),
+		defaultName: defaultSpanName,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.tracer = o.start()
+	return o
+}
+
+// start starts DataDog tracer
+func (o *opentracer) start() opentracing.Tracer {
+	return dd.New(append(o.startOpts, tracer.WithServiceName(o.serviceName))...)
+}
+
+// closeTimeout limits waiting of queued traces on close
+const closeTimeout = 5 * time.Second
+
+// Close stops DataDog tracer and sends buffered traces to agent.
+// Finished traces are queued to DataDog tracer asynchronously and
+// queued traces are dropped on stop, so Close waits until queue is
+// empty first. Spans finished after tracer is closed are dropped.
+func (o *opentracer) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.closed {
+		o.closed = true
+		for deadline := time.Now().Add(closeTimeout); queuedTraces(o.tracer) > 0 && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+		ddtracer.Stop()
 	}
+	return nil
+}
+
+// queuedTraces returns number of finished traces queued to DataDog tracer,
+// which are not yet added to payload sent to agent. DataDog tracer does not
+// expose its queue, so length of queue is read with reflection. -1 is
+// returned, if tracer does not have expected fields.
+func queuedTraces(t opentracing.Tracer) int {
+	v := reflect.ValueOf(t)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	// DataDog opentracer embeds DataDog tracer
+	if v.Kind() != reflect.Struct || v.NumField() == 0 {
+		return -1
+	}
+	v = v.Field(0)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return -1
+	}
+	if queue := v.FieldByName("payloadQueue"); queue.Kind() == reflect.Chan {
+		return queue.Len()
+	}
+	return -1
 }
 
 // StartSpan wraps DataDog opentracing tracer StartSpan to change
-// operation name and component name to be correct in Datadog perspective
+// operation name and component name to be correct in Datadog perspective.
+// Datadog operation name and resource are resolved with name resolver
+// or from span kind and component tags using name rules of tracer.
+// Peer service and hostname given as start options are used as service
+// name and target host, so that called services are shown in service map.
+// Resource and service names given as start options have precedence over
+// operation name and service name of tracer.
 func (o *opentracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
 	ddopts := []opentracing.StartSpanOption{}
-	componentName := "http.request"
+	sso := opentracing.StartSpanOptions{Tags: map[string]interface{}{}}
 	for _, opt := range opts {
-		if c, ok := opt.(component); ok {
-			componentName = c.component
-		}
+		opt.Apply(&sso)
 	}
 
 	ddopts = append(ddopts, opts...)
-	ddopts = append(ddopts, dd.ResourceName(operationName))
-	ddopts = append(ddopts, dd.ServiceName(o.serviceName))
+	if ref, ok := followsFromParent(sso.References); ok {
+		ddopts = append(ddopts, opentracing.ChildOf(ref))
+	}
+	name, resource := o.resolveName(operationName, &sso)
+	ddopts = append(ddopts, dd.ResourceName(resource))
+	ddopts = append(ddopts, dd.ServiceName(o.spanServiceName(&sso)))
+	if host, ok := sso.Tags[string(ext.PeerHostname)].(string); ok && host != "" {
+		ddopts = append(ddopts, opentracing.Tag{Key: ddext.TargetHost, Value: host})
+	}
+
+	return o.tracer.StartSpan(name, ddopts...)
+}
+
+// followsFromParent returns context of first follows-from reference, if
+// references have no child-of reference. Datadog tracer ignores follows-from
+// references, so span would otherwise start new trace.
+func followsFromParent(refs []opentracing.SpanReference) (opentracing.SpanContext, bool) {
+	var parent opentracing.SpanContext
+	for _, ref := range refs {
+		switch ref.Type {
+		case opentracing.ChildOfRef:
+			return nil, false
+		case opentracing.FollowsFromRef:
+			if parent == nil {
+				parent = ref.ReferencedContext
+			}
+		}
+	}
+	return parent, parent != nil
+}
 
-	return o.tracer.StartSpan(componentName, ddopts...)
+// resolveName resolves Datadog operation name and resource for span
+// using name resolver, name rules and resource given in start options
+func (o *opentracer) resolveName(operationName string, sso *opentracing.StartSpanOptions) (string, string) {
+	var name, resource string
+	if o.resolver != nil {
+		name, resource = o.resolver(operationName, *sso)
+	}
+	if name == "" {
+		name = spanName(o.nameRules, o.defaultName, sso)
+	}
+	if resource == "" {
+		resource = spanResourceName(operationName, sso)
+	}
+	return name, resource
+}
+
+// spanServiceName returns service name or peer service from start options
+// or service name of tracer, if neither of those is given
+func (o *opentracer) spanServiceName(sso *opentracing.StartSpanOptions) string {
+	if service, ok := sso.Tags[ddext.ServiceName].(string); ok && service != "" {
+		return service
+	}
+	if service, ok := sso.Tags[string(ext.PeerService)].(string); ok && service != "" {
+		return service
+	}
+	return o.serviceName
+}
+
+// spanResourceName returns resource name from start options
+// or operation name, if resource name is not given
+func spanResourceName(operationName string, sso *opentracing.StartSpanOptions) string {
+	if resource, ok := sso.Tags[ddext.ResourceName].(string); ok && resource != "" {
+		return resource
+	}
+	return operationName
 }
 
 // Inject directly calls DataDog opentracer tracer Inject function
@@ -4,7 +4,11 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer/fakeagent"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
 func TestComponentApply(t *testing.T) {
@@ -74,3 +78,155 @@ func TestComponentOption(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanServiceName(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []opentracing.StartSpanOption
+		want string
+	}{
+		{"no options", nil, "service"},
+		{"empty peer service", []opentracing.StartSpanOption{opentracing.Tag{Key: "peer.service", Value: ""}}, "service"},
+		{"peer service", []opentracing.StartSpanOption{opentracing.Tag{Key: "peer.service", Value: "payments-api"}}, "payments-api"},
+		{"service option over peer service", []opentracing.StartSpanOption{
+			opentracing.Tag{Key: "peer.service", Value: "payments-api"},
+			ServiceOption("payments"),
+		}, "payments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &opentracer{serviceName: "service"}
+			sso := opentracing.StartSpanOptions{Tags: map[string]interface{}{}}
+			for _, opt := range tt.opts {
+				opt.Apply(&sso)
+			}
+			if got := o.spanServiceName(&sso); got != tt.want {
+				t.Errorf("spanServiceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpanResourceName(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []opentracing.StartSpanOption
+		want string
+	}{
+		{"no options", nil, "operation"},
+		{"empty resource name", []opentracing.StartSpanOption{opentracing.Tag{Key: "resource.name", Value: ""}}, "operation"},
+		{"resource name", []opentracing.StartSpanOption{opentracing.Tag{Key: "resource.name", Value: "POST /v1/orders"}}, "POST /v1/orders"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sso := opentracing.StartSpanOptions{Tags: map[string]interface{}{}}
+			for _, opt := range tt.opts {
+				opt.Apply(&sso)
+			}
+			if got := spanResourceName("operation", &sso); got != tt.want {
+				t.Errorf("spanResourceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracerClose(t *testing.T) {
+	agent := fakeagent.New()
+	defer agent.Close()
+	tr := NewTracer("test", WithStartOptions(ddtracer.WithAgentAddr(agent.Addr())))
+	for i := 0; i < 10; i++ {
+		tr.StartSpan("operation").Finish()
+	}
+	if queuedTraces(tr.(*opentracer).tracer) < 0 {
+		t.Errorf("queue of DataDog tracer not found")
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+	if err := tr.Close(); err != nil {
+		t.Errorf("unexpected error when closed twice: %v", err)
+	}
+	if spans := agent.Spans(); len(spans) != 10 {
+		t.Errorf("incorrect number of spans sent on close: %d", len(spans))
+	}
+}
+
+func TestTracerAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []opentracing.StartSpanOption
+		expected fakeagent.Span
+		meta     map[string]string
+		metrics  map[string]float64
+	}{
+		{
+			name:     "defaults",
+			expected: fakeagent.Span{Name: "http.request", Service: "orders", Resource: "GET /orders"},
+		},
+		{
+			name: "server span",
+			opts: []opentracing.StartSpanOption{
+				ext.SpanKindRPCServer,
+				ComponentOption("net/http"),
+				SpanTypeOption(ddext.SpanTypeWeb),
+				ResourceOption("GET /orders/:id"),
+				opentracing.Tag{Key: "order_id", Value: 42},
+			},
+			expected: fakeagent.Span{Name: "net/http", Service: "orders", Resource: "GET /orders/:id", Type: "web"},
+			meta:     map[string]string{"span.kind": "server"},
+			metrics:  map[string]float64{"order_id": 42},
+		},
+		{
+			name: "client span",
+			opts: []opentracing.StartSpanOption{
+				ext.SpanKindRPCClient,
+				opentracing.Tag{Key: string(ext.PeerService), Value: "payments"},
+				opentracing.Tag{Key: string(ext.PeerHostname), Value: "payments.local"},
+			},
+			expected: fakeagent.Span{Name: "http.request", Service: "payments", Resource: "GET /orders"},
+			meta:     map[string]string{ddext.TargetHost: "payments.local"},
+		},
+	}
+
+	agent := fakeagent.New()
+	defer agent.Close()
+	tr := NewTracer("orders", WithStartOptions(ddtracer.WithAgentAddr(agent.Addr())))
+	for _, tt := range tests {
+		span := tr.StartSpan("GET /orders", append(tt.opts, opentracing.Tag{Key: "case", Value: tt.name})...)
+		ext.Error.Set(span, tt.name == "client span")
+		span.Finish()
+	}
+	tr.Close()
+
+	received := map[string]fakeagent.Span{}
+	for _, span := range agent.Spans() {
+		received[span.Meta["case"]] = span
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := received[tt.name]
+			if !ok {
+				t.Fatalf("span not received by agent")
+			}
+			if got.Name != tt.expected.Name || got.Service != tt.expected.Service ||
+				got.Resource != tt.expected.Resource || got.Type != tt.expected.Type {
+				t.Errorf("incorrect span: %+v, expected: %+v", got, tt.expected)
+			}
+			if (got.Error == 1) != (tt.name == "client span") {
+				t.Errorf("incorrect error status: %d", got.Error)
+			}
+			for k, v := range tt.meta {
+				if got.Meta[k] != v {
+					t.Errorf("incorrect meta %s: %s, expected: %s", k, got.Meta[k], v)
+				}
+			}
+			for k, v := range tt.metrics {
+				if got.Metrics[k] != v {
+					t.Errorf("incorrect metric %s: %v, expected: %v", k, got.Metrics[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package opentracer
+
+import (
+	"github.com/opentracing/opentracing-go"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+// measuredTag marks span to be measured by Datadog,
+// even if it is not top level span of service
+const measuredTag = "_dd.measured"
+
+// setTag sets tag to start options, if value is given
+func setTag(opts *opentracing.StartSpanOptions, key string, value interface{}) {
+	if opts == nil {
+		return
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return
+	}
+	if opts.Tags == nil {
+		opts.Tags = map[string]interface{}{}
+	}
+	opts.Tags[key] = value
+}
+
+type spanType struct {
+	spanType string
+}
+
+func (s spanType) Apply(opts *opentracing.StartSpanOptions) {
+	setTag(opts, ddext.SpanType, s.spanType)
+}
+
+// SpanTypeOption can give type for span, e.g. "web", "http" or "sql"
+func SpanTypeOption(t string) opentracing.StartSpanOption {
+	return spanType{t}
+}
+
+type resource struct {
+	resource string
+}
+
+func (r resource) Apply(opts *opentracing.StartSpanOptions) {
+	setTag(opts, ddext.ResourceName, r.resource)
+}
+
+// ResourceOption can give resource name for span,
+// instead of using operation name as resource
+func ResourceOption(r string) opentracing.StartSpanOption {
+	return resource{r}
+}
+
+type service struct {
+	service string
+}
+
+func (s service) Apply(opts *opentracing.StartSpanOptions) {
+	setTag(opts, ddext.ServiceName, s.service)
+}
+
+// ServiceOption can give service name for span,
+// instead of using service name of tracer
+func ServiceOption(s string) opentracing.StartSpanOption {
+	return service{s}
+}
+
+type measured struct{}
+
+func (m measured) Apply(opts *opentracing.StartSpanOptions) {
+	setTag(opts, measuredTag, 1)
+}
+
+// MeasuredOption marks span to be measured, so that metrics
+// are calculated for it even if it is not top level span
+func MeasuredOption() opentracing.StartSpanOption {
+	return measured{}
+}
+
+type analytics struct {
+	rate float64
+}
+
+func (a analytics) Apply(opts *opentracing.StartSpanOptions) {
+	setTag(opts, ddext.EventSampleRate, a.rate)
+}
+
+// AnalyticsOption sets rate at which span is sampled
+// as Trace Search & Analytics event
+func AnalyticsOption(rate float64) opentracing.StartSpanOption {
+	return analytics{rate}
+}
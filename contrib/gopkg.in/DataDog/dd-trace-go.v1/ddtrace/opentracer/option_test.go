@@ -0,0 +1,40 @@
+package opentracer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func TestOptionApply(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  opentracing.StartSpanOption
+		want map[string]interface{}
+	}{
+		{"span type", SpanTypeOption("web"), map[string]interface{}{"span.type": "web"}},
+		{"empty span type", SpanTypeOption(""), map[string]interface{}{}},
+		{"resource", ResourceOption("GET /users"), map[string]interface{}{"resource.name": "GET /users"}},
+		{"empty resource", ResourceOption(""), map[string]interface{}{}},
+		{"service", ServiceOption("users"), map[string]interface{}{"service.name": "users"}},
+		{"empty service", ServiceOption(""), map[string]interface{}{}},
+		{"measured", MeasuredOption(), map[string]interface{}{"_dd.measured": 1}},
+		{"analytics", AnalyticsOption(0.5), map[string]interface{}{"_dd1.sr.eausr": 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// nil options must not panic
+			tt.opt.Apply(nil)
+
+			opts := &opentracing.StartSpanOptions{}
+			tt.opt.Apply(opts)
+			if opts.Tags == nil {
+				opts.Tags = map[string]interface{}{}
+			}
+			if !reflect.DeepEqual(opts.Tags, tt.want) {
+				t.Errorf("Apply() tags = %v, want %v", opts.Tags, tt.want)
+			}
+		})
+	}
+}
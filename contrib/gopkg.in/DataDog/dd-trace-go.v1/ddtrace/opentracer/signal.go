@@ -0,0 +1,38 @@
+package opentracer
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// CloseOnSignal closes tracer, when process receives one of given signals.
+// SIGINT and SIGTERM are used, if signals are not given. Returned channel
+// is closed after tracer is closed, so that main can wait for buffered
+// traces to be sent before process exits. After tracer is closed, signal
+// is raised again, so that process exits like without CloseOnSignal,
+// unless signal is handled elsewhere with signal.Notify.
+//
+// Services, which drain in-flight requests on shutdown, should rather
+// close tracer after draining, so that spans of those requests are sent.
+func CloseOnSignal(t io.Closer, signals ...os.Signal) <-chan struct{} {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		sig := <-c
+		signal.Stop(c)
+		t.Close()
+		close(done)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(sig)
+		}
+	}()
+
+	return done
+}
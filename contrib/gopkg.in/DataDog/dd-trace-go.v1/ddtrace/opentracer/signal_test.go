@@ -0,0 +1,54 @@
+//go:build !windows
+// +build !windows
+
+package opentracer
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type closer struct {
+	closed chan struct{}
+}
+
+func (c *closer) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestCloseOnSignal(t *testing.T) {
+	// signal raised again after close would kill test process without handler
+	raised := make(chan os.Signal, 2)
+	signal.Notify(raised, syscall.SIGUSR1)
+	defer signal.Stop(raised)
+
+	c := &closer{closed: make(chan struct{})}
+	done := CloseOnSignal(c, syscall.SIGUSR1)
+
+	p, _ := os.FindProcess(os.Getpid())
+	p.Signal(syscall.SIGUSR1)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tracer not closed on signal")
+	}
+	select {
+	case <-c.closed:
+	default:
+		t.Error("done closed before tracer")
+	}
+
+	// first signal is delivered to both handlers, second one is raised again
+	for i := 0; i < 2; i++ {
+		select {
+		case <-raised:
+		case <-time.After(5 * time.Second):
+			t.Fatal("signal not raised again after close")
+		}
+	}
+}
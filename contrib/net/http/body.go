@@ -0,0 +1,269 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// BodyCapture configures capturing of request and response bodies
+// of outgoing calls to span logs. Bodies are captured while they are
+// read by transport or caller, so streaming is not affected.
+type BodyCapture struct {
+	// MaxSize is maximum number of bytes captured from each body.
+	// Capturing is disabled, if size is not positive.
+	MaxSize int
+	// ContentTypes limits capturing to bodies with given media types,
+	// e.g. "application/json" or "text/*". Empty list captures all bodies.
+	ContentTypes []string
+	// OnlyErrors captures bodies only when call fails or
+	// response status code is 400 or greater
+	OnlyErrors bool
+	// RedactFields lists JSON field names, whose values are replaced
+	// in captured bodies. Names are case-insensitive and object and
+	// array values are replaced as whole.
+	RedactFields []string
+}
+
+// redacted replaces values of redacted JSON fields
+const redacted = `"[REDACTED]"`
+
+// bodyCaptureConfig holds body capture settings and lower cased redacted fields
+type bodyCaptureConfig struct {
+	BodyCapture
+	redact map[string]bool
+}
+
+func newBodyCaptureConfig(c BodyCapture) *bodyCaptureConfig {
+	cfg := &bodyCaptureConfig{BodyCapture: c}
+	if len(c.RedactFields) > 0 {
+		cfg.redact = make(map[string]bool, len(c.RedactFields))
+		for _, f := range c.RedactFields {
+			cfg.redact[strings.ToLower(f)] = true
+		}
+	}
+	return cfg
+}
+
+// captures checks if body with given headers should be captured
+func (c *bodyCaptureConfig) captures(h http.Header) bool {
+	if len(c.ContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, ct := range c.ContentTypes {
+		ct = strings.ToLower(ct)
+		if ct == mediaType || (strings.HasSuffix(ct, "/*") && strings.HasPrefix(mediaType, ct[:len(ct)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// start wraps request body, so that it is captured while transport reads it
+func (c *bodyCaptureConfig) start(span opentracing.Span, req *http.Request) (*http.Request, *bodyCapture) {
+	if c == nil || c.MaxSize <= 0 {
+		return req, nil
+	}
+	bc := &bodyCapture{
+		cfg:  c,
+		span: span,
+	}
+	if req.Body != nil && req.Body != http.NoBody && c.captures(req.Header) {
+		bc.request = &capturedBody{
+			ReadCloser: req.Body,
+			max:        c.MaxSize,
+		}
+		req = req.WithContext(req.Context())
+		req.Body = bc.request
+	}
+	return req, bc
+}
+
+// bodyCapture captures bodies of single outgoing call
+type bodyCapture struct {
+	cfg     *bodyCaptureConfig
+	span    opentracing.Span
+	request *capturedBody
+}
+
+// finish logs captured request body and calls finish. If response body is
+// captured, finish is called when caller closes response body.
+func (bc *bodyCapture) finish(res *http.Response, err error, finish func()) *http.Response {
+	if bc == nil || (bc.cfg.OnlyErrors && err == nil && res.StatusCode < http.StatusBadRequest) {
+		finish()
+		return res
+	}
+	if bc.request != nil {
+		bc.log("http.request.body", bc.request)
+	}
+	if err != nil || res.Body == nil || res.Body == http.NoBody ||
+		res.StatusCode == http.StatusSwitchingProtocols || !bc.cfg.captures(res.Header) {
+		finish()
+		return res
+	}
+
+	response := &capturedBody{
+		ReadCloser: res.Body,
+		max:        bc.cfg.MaxSize,
+	}
+	response.onClose = func() {
+		bc.log("http.response.body", response)
+		finish()
+	}
+	res.Body = response
+	return res
+}
+
+func (bc *bodyCapture) log(event string, b *capturedBody) {
+	body, truncated := b.captured()
+	if bc.cfg.redact != nil {
+		body = redactJSON(body, bc.cfg.redact)
+	}
+	bc.span.LogFields(
+		log.String("event", event),
+		log.String("http.body", body),
+		log.Bool("http.body.truncated", truncated),
+	)
+}
+
+// capturedBody copies up to max bytes of body, when it is read
+type capturedBody struct {
+	io.ReadCloser
+	max     int
+	onClose func()
+
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated bool
+	once      sync.Once
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.mu.Lock()
+		remaining := b.max - b.buf.Len()
+		if n > remaining {
+			b.buf.Write(p[:remaining])
+			b.truncated = true
+		} else {
+			b.buf.Write(p[:n])
+		}
+		b.mu.Unlock()
+	}
+	return n, err
+}
+
+func (b *capturedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if b.onClose != nil {
+		b.once.Do(b.onClose)
+	}
+	return err
+}
+
+// captured returns body captured so far
+func (b *capturedBody) captured() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String(), b.truncated
+}
+
+// redactJSON replaces values of given lower cased fields in JSON body.
+// Body may be cut by size limit, so values are replaced up to the end
+// of body, if they are not terminated.
+func redactJSON(body string, fields map[string]bool) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(body); {
+		if body[i] != '"' {
+			b.WriteByte(body[i])
+			i++
+			continue
+		}
+		end := endOfJSONString(body, i)
+		name := body[i:end]
+		b.WriteString(name)
+		i = end
+
+		j := skipJSONSpace(body, i)
+		if j >= len(body) || body[j] != ':' || !fields[strings.ToLower(unquote(name))] {
+			continue
+		}
+		j = skipJSONSpace(body, j+1)
+		if j >= len(body) {
+			continue
+		}
+		b.WriteString(body[i:j])
+		b.WriteString(redacted)
+		i = endOfJSONValue(body, j)
+	}
+	return b.String()
+}
+
+// endOfJSONValue returns index after string, object, array
+// or scalar value starting at given position
+func endOfJSONValue(body string, start int) int {
+	switch body[start] {
+	case '"':
+		return endOfJSONString(body, start)
+	case '{', '[':
+		depth := 0
+		for i := start; i < len(body); i++ {
+			switch body[i] {
+			case '"':
+				i = endOfJSONString(body, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return len(body)
+	}
+	i := start
+	for i < len(body) && strings.IndexByte(",}] \t\r\n", body[i]) < 0 {
+		i++
+	}
+	return i
+}
+
+// endOfJSONString returns index after string starting at given position
+func endOfJSONString(body string, start int) int {
+	for i := start + 1; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(body)
+}
+
+func skipJSONSpace(body string, i int) int {
+	for i < len(body) && strings.IndexByte(" \t\r\n", body[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// unquote returns string without surrounding quotes
+func unquote(s string) string {
+	s = s[1:]
+	if len(s) > 0 && s[len(s)-1] == '"' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
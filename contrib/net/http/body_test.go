@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestBodyCaptureConfigCaptures(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentTypes []string
+		contentType  string
+		want         bool
+	}{
+		{"no content types", nil, "", true},
+		{"matching content type", []string{"application/json"}, "application/json; charset=utf-8", true},
+		{"wildcard content type", []string{"text/*"}, "text/plain", true},
+		{"other content type", []string{"application/json"}, "application/octet-stream", false},
+		{"missing content type", []string{"application/json"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newBodyCaptureConfig(BodyCapture{MaxSize: 10, ContentTypes: tt.contentTypes})
+			h := http.Header{}
+			h.Set("Content-Type", tt.contentType)
+			if got := c.captures(h); got != tt.want {
+				t.Errorf("captures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapExternalClientBodyCapture(t *testing.T) {
+	tests := []struct {
+		name     string
+		capture  BodyCapture
+		status   int
+		request  string
+		response string
+		logs     map[string]string
+	}{
+		{"disabled", BodyCapture{}, http.StatusOK, `{"a":1}`, `{"b":2}`, map[string]string{}},
+		{"request and response", BodyCapture{MaxSize: 100}, http.StatusOK, `{"a":1}`, `{"b":2}`, map[string]string{
+			"http.request.body":  `{"a":1}`,
+			"http.response.body": `{"b":2}`,
+		}},
+		{"truncated", BodyCapture{MaxSize: 4}, http.StatusOK, `{"a":1}`, `{"b":2}`, map[string]string{
+			"http.request.body":  `{"a"`,
+			"http.response.body": `{"b"`,
+		}},
+		{"only errors without error", BodyCapture{MaxSize: 100, OnlyErrors: true}, http.StatusOK, `{"a":1}`, `{"b":2}`, map[string]string{}},
+		{"only errors with error", BodyCapture{MaxSize: 100, OnlyErrors: true}, http.StatusBadRequest, `{"a":1}`, `{"b":2}`, map[string]string{
+			"http.request.body":  `{"a":1}`,
+			"http.response.body": `{"b":2}`,
+		}},
+		{"other content type", BodyCapture{MaxSize: 100, ContentTypes: []string{"text/plain"}}, http.StatusOK, `{"a":1}`, `{"b":2}`, map[string]string{}},
+		{"redacted fields", BodyCapture{MaxSize: 100, RedactFields: []string{"card_number", "cvv"}}, http.StatusOK,
+			`{"card_number": "4111 1111", "cvv":123, "amount":10}`, `{"token":"t","Card_Number":"4111"}`, map[string]string{
+				"http.request.body":  `{"card_number": "[REDACTED]", "cvv":"[REDACTED]", "amount":10}`,
+				"http.response.body": `{"token":"t","Card_Number":"[REDACTED]"}`,
+			}},
+		{"redacted object and array fields", BodyCapture{MaxSize: 200, RedactFields: []string{"card", "cards"}}, http.StatusOK,
+			`{"card": {"number": "4111111111111111", "cvv": "123"}, "cards":[{"n":"[1]"}], "note":"card"}`, `{"items":[{"card":{}}]}`, map[string]string{
+				"http.request.body":  `{"card": "[REDACTED]", "cards":"[REDACTED]", "note":"card"}`,
+				"http.response.body": `{"items":[{"card":"[REDACTED]"}]}`,
+			}},
+		{"redacted truncated object", BodyCapture{MaxSize: 30, RedactFields: []string{"card"}}, http.StatusOK,
+			`{"card": {"number": "4111111111111111"}}`, ``, map[string]string{
+				"http.request.body": `{"card": "[REDACTED]"`,
+			}},
+		{"redacted truncated field", BodyCapture{MaxSize: 20, RedactFields: []string{"card_number"}}, http.StatusOK,
+			`{"card_number":"4111 1111"}`, ``, map[string]string{
+				"http.request.body": `{"card_number":"[REDACTED]"`,
+			}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ct := mocktracer.New()
+			opentracing.SetGlobalTracer(ct)
+			defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				if string(body) != test.request {
+					t.Errorf("request body changed: %s", body)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			client := WrapExternalClient(server.Client(), "", WithBodyCapture(test.capture))
+
+			rspan := ct.StartSpan("root_span")
+			ctx := opentracing.ContextWithSpan(context.Background(), rspan)
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/test", strings.NewReader(test.request))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := client.Do(req.WithContext(ctx))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, _ := ioutil.ReadAll(res.Body)
+			if string(body) != test.response {
+				t.Errorf("response body changed: %s", body)
+			}
+			res.Body.Close()
+			rspan.Finish()
+
+			spans := ct.FinishedSpans()
+			if len(spans) != 2 {
+				t.Fatalf("There should be root span and request span")
+			}
+			logs := map[string]string{}
+			for _, record := range spans[0].Logs() {
+				var event, body string
+				for _, f := range record.Fields {
+					switch f.Key {
+					case "event":
+						event = f.ValueString
+					case "http.body":
+						body = f.ValueString
+					}
+				}
+				logs[event] = body
+			}
+			if len(logs) != len(test.logs) {
+				t.Errorf("incorrect body logs: %v", logs)
+			}
+			for event, want := range test.logs {
+				if logs[event] != want {
+					t.Errorf("incorrect %s: '%s', want '%s'", event, logs[event], want)
+				}
+			}
+		})
+	}
+}
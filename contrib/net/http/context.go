@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type operationNameKey struct{}
+
+// WithOperationName overrides operation name of span created
+// for outgoing request made with given context
+func WithOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// operationNameFromContext returns operation name override from context
+func operationNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(operationNameKey{}).(string)
+	return name
+}
+
+type spanTagsKey struct{}
+
+// WithSpanTags adds tags to span created for outgoing request made with
+// given context. Tags are merged with tags already stored to context.
+func WithSpanTags(ctx context.Context, tags opentracing.Tags) context.Context {
+	merged := opentracing.Tags{}
+	for k, v := range spanTagsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, spanTagsKey{}, merged)
+}
+
+// spanTagsFromContext returns span tags stored to context
+func spanTagsFromContext(ctx context.Context) opentracing.Tags {
+	tags, _ := ctx.Value(spanTagsKey{}).(opentracing.Tags)
+	return tags
+}
+
+type resourceNameKey struct{}
+
+// WithResourceName sets resource name of span created
+// for outgoing request made with given context
+func WithResourceName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, resourceNameKey{}, name)
+}
+
+// resourceNameFromContext returns resource name stored to context
+func resourceNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(resourceNameKey{}).(string)
+	return name
+}
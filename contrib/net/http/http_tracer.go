@@ -3,6 +3,8 @@ package http
 import (
 	"net/http"
 
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/utils"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 )
@@ -11,6 +13,7 @@ import (
 type roundTripper struct {
 	operationName string
 	base          http.RoundTripper
+	cfg           *config
 }
 
 // RoundTrip for internal calls does not start new span as it assumes that called
@@ -19,9 +22,12 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (res *http.Response, err er
 	rootSpan := opentracing.SpanFromContext(req.Context())
 	if rootSpan != nil {
 		// context contains span, create new child span
-		span, _ := opentracing.StartSpanFromContext(req.Context(), rt.operationName)
+		span, _ := utils.StartSpanFromContext(req.Context(), rt.cfg.spanOperationName(req, rt.operationName), rt.cfg.startSpanOptions(req)...)
 		ext.HTTPMethod.Set(span, req.Method)
 		ext.HTTPUrl.Set(span, req.URL.Path)
+
+		var capture *bodyCapture
+		req, capture = rt.cfg.bodyCapture.start(span, req)
 		defer func() {
 			if err == nil {
 				ext.HTTPStatusCode.Set(span, uint16(res.StatusCode))
@@ -29,21 +35,64 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (res *http.Response, err er
 				span.SetTag("server.errors", err.Error())
 				ext.Error.Set(span, true)
 			}
-			span.Finish()
+			res = capture.finish(res, err, span.Finish)
 		}()
 
 		opentracing.GlobalTracer().Inject(
 			span.Context(),
 			opentracing.HTTPHeaders,
-			opentracing.HTTPHeadersCarrier(req.Header))
+			rt.cfg.baggageLimits.Writer(span.Context(), opentracing.HTTPHeadersCarrier(req.Header)))
 	}
 
 	return rt.base.RoundTrip(req)
 }
 
+// spanOperationName resolves operation name of span for request. Name given
+// in request context has precedence over resolver and default name.
+func (cfg *config) spanOperationName(req *http.Request, on string) string {
+	if name := operationNameFromContext(req.Context()); name != "" {
+		return name
+	}
+	if cfg.operationName != nil {
+		if name := cfg.operationName(req); name != "" {
+			return name
+		}
+	}
+	return on
+}
+
+// startSpanOptions returns options describing called service of request
+// and tags given in request context
+func (cfg *config) startSpanOptions(req *http.Request) []opentracing.StartSpanOption {
+	opts := []opentracing.StartSpanOption{}
+
+	service := cfg.peerService
+	if service == "" {
+		if s, ok := cfg.peerServices[req.URL.Host]; ok {
+			service = s
+		} else {
+			service = cfg.peerServices[req.URL.Hostname()]
+		}
+	}
+	if service != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerService), Value: service})
+	}
+	if host := req.URL.Hostname(); host != "" {
+		opts = append(opts, opentracing.Tag{Key: string(ext.PeerHostname), Value: host})
+	}
+	if tags := spanTagsFromContext(req.Context()); len(tags) > 0 {
+		opts = append(opts, tags)
+	}
+	if name := resourceNameFromContext(req.Context()); name != "" {
+		opts = append(opts, opentracer.ResourceOption(name))
+	}
+
+	return opts
+}
+
 // WrapClient wraps http.Client to inject opentracing span to outgoing call
 // if and only if root span exists in request context.
-func WrapClient(c *http.Client, on string) *http.Client {
+func WrapClient(c *http.Client, on string, opts ...Option) *http.Client {
 	rt := http.DefaultTransport
 	if c.Transport != nil {
 		rt = c.Transport
@@ -56,6 +105,7 @@ func WrapClient(c *http.Client, on string) *http.Client {
 		Transport: &roundTripper{
 			base:          rt,
 			operationName: on,
+			cfg:           newConfig(opts...),
 		},
 		CheckRedirect: c.CheckRedirect,
 		Jar:           c.Jar,
@@ -67,21 +117,22 @@ func WrapClient(c *http.Client, on string) *http.Client {
 type externalRoundTripper struct {
 	base          http.RoundTripper
 	operationName string
+	cfg           *config
 }
 
 // RoundTrip creates new span, but don't inject it to request headers as this is intended to use
 // with external services.
-func (rt *externalRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	var res *http.Response
-	var err error
-
+func (rt *externalRoundTripper) RoundTrip(req *http.Request) (res *http.Response, err error) {
 	ctx := req.Context()
 	span := opentracing.SpanFromContext(ctx)
 	if span != nil {
 		// context contains span, create new child span
-		span, ctx = opentracing.StartSpanFromContext(ctx, rt.operationName)
+		span, ctx = utils.StartSpanFromContext(ctx, rt.cfg.spanOperationName(req, rt.operationName), rt.cfg.startSpanOptions(req)...)
 		ext.HTTPMethod.Set(span, req.Method)
 		ext.HTTPUrl.Set(span, req.URL.Path)
+
+		var capture *bodyCapture
+		req, capture = rt.cfg.bodyCapture.start(span, req)
 		defer func() {
 			if err == nil {
 				ext.HTTPStatusCode.Set(span, uint16(res.StatusCode))
@@ -89,7 +140,7 @@ func (rt *externalRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 				span.SetTag("server.errors", err.Error())
 				ext.Error.Set(span, true)
 			}
-			span.Finish()
+			res = capture.finish(res, err, span.Finish)
 		}()
 	}
 
@@ -102,7 +153,7 @@ func (rt *externalRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 // opentracing span to outgoing calls, if and only if context
 // of request contains root span. default operation name for that
 // span is http.request
-func WrapExternalClient(c *http.Client, on string) *http.Client {
+func WrapExternalClient(c *http.Client, on string, opts ...Option) *http.Client {
 	rt := http.DefaultTransport
 	if c.Transport != nil {
 		rt = c.Transport
@@ -115,6 +166,7 @@ func WrapExternalClient(c *http.Client, on string) *http.Client {
 		Transport: &externalRoundTripper{
 			base:          rt,
 			operationName: on,
+			cfg:           newConfig(opts...),
 		},
 		CheckRedirect: c.CheckRedirect,
 		Jar:           c.Jar,
@@ -4,8 +4,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/foodiefm/opentracing/utils"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 )
@@ -151,6 +154,218 @@ func TestExternalWrapClient(t *testing.T) {
 	}
 }
 
+func TestWrapClientBaggage(t *testing.T) {
+	ct := mocktracer.New()
+	opentracing.SetGlobalTracer(ct)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc, err := ct.Extract(
+			opentracing.HTTPHeaders,
+			opentracing.HTTPHeadersCarrier(r.Header))
+		if err != nil {
+			t.Errorf("There is no span in request")
+			return
+		}
+		items := 0
+		sc.ForeachBaggageItem(func(k, v string) bool {
+			items++
+			return true
+		})
+		if items != 1 {
+			t.Errorf("Baggage items exceeding limits were injected")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := WrapClient(server.Client(), "", WithBaggageLimits(utils.BaggageLimits{MaxItems: 1, KeyPrefix: "mockpfx-baggage-"}))
+
+	rspan := ct.StartSpan("root_span")
+	rspan.SetBaggageItem("experiment", "a")
+	rspan.SetBaggageItem("tenant_id", "42")
+	ctx := opentracing.ContextWithSpan(context.Background(), rspan)
+	ctx = utils.ContextWithBaggageTags(ctx, "tenant_id")
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/test", nil)
+	client.Do(req.WithContext(ctx))
+	rspan.Finish()
+
+	spans := ct.FinishedSpans()
+	if len(spans) != 2 {
+		t.Fatalf("There should be root span and request span")
+	}
+	if v := spans[0].Tag("tenant_id"); v != "42" {
+		t.Errorf("baggage item not promoted to tag: %v", v)
+	}
+}
+
+func TestWrapClientPeerService(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want interface{}
+	}{
+		{"no peer service", nil, nil},
+		{"static peer service", []Option{WithPeerService("payments-api")}, "payments-api"},
+		{"peer service from hostname", []Option{WithPeerServiceMapping(map[string]string{"127.0.0.1": "local-api"})}, "local-api"},
+		{"static peer service over mapping", []Option{
+			WithPeerService("payments-api"),
+			WithPeerServiceMapping(map[string]string{"127.0.0.1": "local-api"}),
+		}, "payments-api"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ct := mocktracer.New()
+			opentracing.SetGlobalTracer(ct)
+			defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := WrapClient(server.Client(), "", test.opts...)
+
+			rspan := ct.StartSpan("root_span")
+			ctx := opentracing.ContextWithSpan(context.Background(), rspan)
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/test", nil)
+			client.Do(req.WithContext(ctx))
+			rspan.Finish()
+
+			spans := ct.FinishedSpans()
+			if len(spans) != 2 {
+				t.Fatalf("There should be root span and request span")
+			}
+			if v := spans[0].Tag("peer.service"); v != test.want {
+				t.Errorf("incorrect peer service: %v", v)
+			}
+			if v := spans[0].Tag("peer.hostname"); v != "127.0.0.1" {
+				t.Errorf("incorrect peer hostname: %v", v)
+			}
+		})
+	}
+}
+
+func TestWrapClientOperationName(t *testing.T) {
+	nameFunc := func(req *http.Request) string {
+		return req.Method + " " + req.URL.Path
+	}
+	tests := []struct {
+		name     string
+		on       string
+		opts     []Option
+		override string
+		want     string
+	}{
+		{"default name", "", nil, "", "http.request"},
+		{"fixed name", "test.request", nil, "", "test.request"},
+		{"name from func", "test.request", []Option{WithOperationNameFunc(nameFunc)}, "", "GET /v1/charges/42"},
+		{"empty name from func", "test.request", []Option{WithOperationNameFunc(func(*http.Request) string { return "" })}, "", "test.request"},
+		{"name from context", "test.request", []Option{WithOperationNameFunc(nameFunc)}, "charge.get", "charge.get"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ct := mocktracer.New()
+			opentracing.SetGlobalTracer(ct)
+			defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			for _, client := range []*http.Client{
+				WrapClient(server.Client(), test.on, test.opts...),
+				WrapExternalClient(server.Client(), test.on, test.opts...),
+			} {
+				rspan := ct.StartSpan("root_span")
+				ctx := opentracing.ContextWithSpan(context.Background(), rspan)
+				if test.override != "" {
+					ctx = WithOperationName(ctx, test.override)
+				}
+				req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/charges/42", nil)
+				client.Do(req.WithContext(ctx))
+				rspan.Finish()
+			}
+
+			spans := ct.FinishedSpans()
+			if len(spans) != 4 {
+				t.Fatalf("There should be root span and request span for both clients")
+			}
+			for _, span := range []*mocktracer.MockSpan{spans[0], spans[2]} {
+				if span.OperationName != test.want {
+					t.Errorf("incorrect operation name: %s", span.OperationName)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapClientContextTags(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, client := range []*http.Client{
+		WrapClient(server.Client(), ""),
+		WrapExternalClient(server.Client(), ""),
+	} {
+		rspan := recorder.StartSpan("root_span")
+		ctx := opentracing.ContextWithSpan(context.Background(), rspan)
+		ctx = WithSpanTags(ctx, opentracing.Tags{"order_id": 1, "customer_id": 2})
+		ctx = WithSpanTags(ctx, opentracing.Tags{"order_id": 42})
+		ctx = WithResourceName(ctx, "POST /v1/orders")
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/orders", nil)
+		client.Do(req.WithContext(ctx))
+		rspan.Finish()
+	}
+
+	if !recorder.AssertSpanCount(t, 4) {
+		return
+	}
+	for _, span := range recorder.FindSpans("http.request") {
+		tracetest.AssertTags(t, span, map[string]interface{}{
+			"order_id":      42,
+			"customer_id":   2,
+			"resource.name": "POST /v1/orders",
+		})
+	}
+}
+
+func ExampleWithSpanTags() {
+	client := WrapClient(&http.Client{}, "")
+
+	span := opentracing.StartSpan("test_call")
+	defer span.Finish()
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost/v1/orders", nil)
+	ctx := opentracing.ContextWithSpan(context.TODO(), span)
+	ctx = WithSpanTags(ctx, opentracing.Tags{"order_id": 42})
+
+	client.Do(req.WithContext(ctx))
+}
+
+func ExampleWithOperationNameFunc() {
+	client := WrapClient(&http.Client{}, "", WithOperationNameFunc(func(req *http.Request) string {
+		return req.Method + " payments-api " + strings.Replace(req.URL.Path, "42", ":id", 1)
+	}))
+
+	span := opentracing.StartSpan("test_call")
+	defer span.Finish()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/v1/charges/42", nil)
+	ctx := opentracing.ContextWithSpan(context.TODO(), span)
+
+	client.Do(req.WithContext(ctx))
+}
+
 func ExampleWrapClient() {
 	client := WrapClient(&http.Client{}, "span.name")
 
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/foodiefm/opentracing/utils"
+)
+
+// config holds optional settings of wrapped clients
+type config struct {
+	baggageLimits utils.BaggageLimits
+	peerService   string
+	peerServices  map[string]string
+	operationName OperationNameFunc
+	bodyCapture   *bodyCaptureConfig
+}
+
+// OperationNameFunc resolves operation name of span for outgoing request.
+// Empty name falls back to operation name given when client was wrapped.
+type OperationNameFunc func(*http.Request) string
+
+// Option typed functions can be used to configure wrapped clients
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithBaggageLimits limits baggage items injected to outgoing requests.
+// Items exceeding limits are still available in span, but they are
+// not sent to called service. KeyPrefix of limits must match baggage
+// key prefix of tracer in use.
+func WithBaggageLimits(limits utils.BaggageLimits) Option {
+	return func(cfg *config) {
+		cfg.baggageLimits = limits
+	}
+}
+
+// WithPeerService sets name of the called service to spans of
+// outgoing requests. Static name has precedence over mapping.
+func WithPeerService(name string) Option {
+	return func(cfg *config) {
+		cfg.peerService = name
+	}
+}
+
+// WithPeerServiceMapping resolves name of the called service from
+// request host, e.g. "payments.internal:8080": "payments-api". Host
+// is matched first with port and then without it.
+func WithPeerServiceMapping(hosts map[string]string) Option {
+	return func(cfg *config) {
+		cfg.peerServices = hosts
+	}
+}
+
+// WithOperationNameFunc resolves operation name of span per request,
+// instead of using one name for all requests made with client
+func WithOperationNameFunc(f OperationNameFunc) Option {
+	return func(cfg *config) {
+		cfg.operationName = f
+	}
+}
+
+// WithBodyCapture captures request and response bodies of
+// outgoing calls to span logs
+func WithBodyCapture(capture BodyCapture) Option {
+	return func(cfg *config) {
+		cfg.bodyCapture = newBodyCaptureConfig(capture)
+	}
+}
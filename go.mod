@@ -3,11 +3,18 @@ module github.com/foodiefm/opentracing
 go 1.13
 
 require (
+	github.com/alicebob/miniredis/v2 v2.11.4
 	github.com/gin-gonic/gin v1.4.0
+	github.com/go-redis/redis/v7 v7.4.0
 	github.com/labstack/echo/v4 v4.1.8
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/philhofer/fwd v1.0.0 // indirect
-	github.com/tinylib/msgp v1.1.0 // indirect
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/segmentio/kafka-go v0.3.5
+	github.com/streadway/amqp v1.0.0
+	github.com/tinylib/msgp v1.1.0
+	golang.org/x/sync v0.0.0-20220907140024-f12130a52804
 	golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
+	google.golang.org/grpc v1.27.1
 	gopkg.in/DataDog/dd-trace-go.v1 v1.16.1
 )
@@ -0,0 +1,112 @@
+// Package jobs implements tracing of background and scheduled jobs. Every
+// run of job gets own root span, which can be linked to trace triggering
+// the job, so that job spans are not orphaned or left out.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"runtime/debug"
+	"time"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+const (
+	// spanType is Datadog span type of job spans
+	spanType = "worker"
+	// component is component tag of job spans
+	component = "jobs"
+
+	tagJobName     = "job.name"
+	tagJobDuration = "job.duration_ms"
+	tagJobPanic    = "job.panic"
+)
+
+// Run runs job with context containing job span. Span does not have parent,
+// but it follows from triggering span given with WithTriggeredBy or from
+// span in given context. Duration of job and returned error are tagged to
+// span. If job panics, panic is recorded to span and job panics again.
+func Run(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...Option) (err error) {
+	cfg := newConfig(opts...)
+	sopts := []opentracing.StartSpanOption{
+		opentracer.SpanTypeOption(spanType),
+		opentracing.Tag{Key: string(ext.Component), Value: component},
+		opentracing.Tag{Key: tagJobName, Value: name},
+		cfg.tags,
+	}
+	if trigger := cfg.triggerContext(ctx); trigger != nil {
+		sopts = append(sopts, opentracing.FollowsFrom(trigger))
+	}
+	span := opentracing.StartSpan(name, sopts...)
+	utils.PromoteContextBaggage(ctx, span)
+	start := time.Now()
+
+	defer func() {
+		span.SetTag(tagJobDuration, float64(time.Since(start))/float64(time.Millisecond))
+		if r := recover(); r != nil {
+			span.SetTag(tagJobPanic, fmt.Sprint(r))
+			span.LogFields(log.String("event", "panic"), log.String("stack", string(debug.Stack())))
+			ext.Error.Set(span, true)
+			span.Finish()
+			panic(r)
+		}
+		if err != nil {
+			span.SetTag("server.errors", err.Error())
+			ext.Error.Set(span, true)
+		}
+		span.Finish()
+	}()
+
+	return fn(opentracing.ContextWithSpan(ctx, span))
+}
+
+// triggerContext returns span context of triggering trace
+func (cfg *config) triggerContext(ctx context.Context) opentracing.SpanContext {
+	if cfg.trigger != "" {
+		if sc, err := DeserializeSpanContext(cfg.trigger); err == nil {
+			return sc
+		}
+	}
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		return span.Context()
+	}
+	return nil
+}
+
+// SerializeSpanContext serializes context of span in given context, so that
+// it can be stored with scheduled job. Empty string is returned, if context
+// has no span.
+func SerializeSpanContext(ctx context.Context) (string, error) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return "", nil
+	}
+	carrier := opentracing.TextMapCarrier{}
+	if err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+		return "", err
+	}
+	values := url.Values{}
+	for k, v := range carrier {
+		values.Set(k, v)
+	}
+	return values.Encode(), nil
+}
+
+// DeserializeSpanContext returns span context serialized with SerializeSpanContext
+func DeserializeSpanContext(serialized string) (opentracing.SpanContext, error) {
+	values, err := url.ParseQuery(serialized)
+	if err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	carrier := opentracing.TextMapCarrier{}
+	for k := range values {
+		carrier[k] = values.Get(k)
+	}
+	return opentracing.GlobalTracer().Extract(opentracing.TextMap, carrier)
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer/fakeagent"
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/opentracing/opentracing-go"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		panics bool
+		tags   map[string]interface{}
+	}{
+		{"success", nil, false, map[string]interface{}{"span.type": "worker", "component": "jobs", "job.name": "success"}},
+		{"error", errors.New("mail server down"), false, map[string]interface{}{"server.errors": "mail server down"}},
+		{"panic", nil, true, map[string]interface{}{"job.panic": "boom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, done := tracetest.Global()
+			defer done()
+
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Errorf("incorrect panic: %v", r)
+					}
+				}()
+				err = Run(context.Background(), tt.name, func(ctx context.Context) error {
+					if opentracing.SpanFromContext(ctx) == nil {
+						t.Errorf("job span not in context")
+					}
+					if tt.panics {
+						panic("boom")
+					}
+					return tt.err
+				})
+			}()
+			if err != tt.err {
+				t.Errorf("incorrect error: %v, expected: %v", err, tt.err)
+			}
+
+			span := recorder.RequireSpan(t, tt.name)
+			if span.ParentID != 0 {
+				t.Errorf("job span has parent")
+			}
+			if _, ok := span.Tag("job.duration_ms").(float64); !ok {
+				t.Errorf("duration not tagged: %v", span.Tag("job.duration_ms"))
+			}
+			tracetest.AssertTags(t, span, tt.tags)
+			tracetest.AssertError(t, span, tt.err != nil || tt.panics)
+		})
+	}
+}
+
+func TestRunTriggeredBy(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "POST /exports")
+	serialized, err := SerializeSpanContext(ctx)
+	if err != nil || serialized == "" {
+		t.Fatalf("unexpected serialized context: '%s', error: %v", serialized, err)
+	}
+	root.Finish()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		opts   []Option
+		linked bool
+	}{
+		{"serialized context", context.Background(), []Option{WithTriggeredBy(serialized)}, true},
+		{"span in context", ctx, nil, true},
+		{"invalid serialized context", context.Background(), []Option{WithTriggeredBy("%zz")}, false},
+		{"no trigger", context.Background(), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Run(tt.ctx, tt.name, func(context.Context) error { return nil }, tt.opts...)
+			span := recorder.RequireSpan(t, tt.name)
+			parent := recorder.FindSpan("POST /exports")
+			if linked := span.SpanContext.TraceID == parent.SpanContext.TraceID; linked != tt.linked {
+				t.Errorf("incorrect link to triggering trace: %v, expected: %v", linked, tt.linked)
+			}
+		})
+	}
+
+	if s, err := SerializeSpanContext(context.Background()); s != "" || err != nil {
+		t.Errorf("unexpected serialized context without span: '%s', %v", s, err)
+	}
+}
+
+func TestRunAgent(t *testing.T) {
+	agent := fakeagent.New()
+	defer agent.Close()
+	tr := opentracer.NewTracer("exports", opentracer.WithStartOptions(ddtracer.WithAgentAddr(agent.Addr())))
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	opentracing.SetGlobalTracer(tr)
+
+	Run(context.Background(), "export.orders", func(context.Context) error { return nil })
+	tr.Close()
+
+	spans := agent.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("incorrect number of spans sent to agent: %d", len(spans))
+	}
+	expected := fakeagent.Span{Name: "jobs", Service: "exports", Resource: "export.orders", Type: "worker"}
+	if s := spans[0]; s.Name != expected.Name || s.Service != expected.Service || s.Resource != expected.Resource || s.Type != expected.Type {
+		t.Errorf("incorrect span: %+v, expected: %+v", s, expected)
+	}
+}
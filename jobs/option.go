@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"github.com/opentracing/opentracing-go"
+)
+
+// config holds optional settings of job run
+type config struct {
+	trigger string
+	tags    opentracing.Tags
+}
+
+// Option typed functions can be used to configure job run
+type Option func(*config)
+
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		tags: opentracing.Tags{},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithTriggeredBy links job span to trace, which triggered job.
+// Span context is serialized with SerializeSpanContext e.g. when
+// job is scheduled, and stored with job until it is run.
+func WithTriggeredBy(serialized string) Option {
+	return func(cfg *config) {
+		cfg.trigger = serialized
+	}
+}
+
+// WithTags gives tags set to job span
+func WithTags(tags opentracing.Tags) Option {
+	return func(cfg *config) {
+		for k, v := range tags {
+			cfg.tags[k] = v
+		}
+	}
+}
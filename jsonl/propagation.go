@@ -0,0 +1,82 @@
+package jsonl
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Keys used in text map carriers. Keys are same as used by
+// basictracer, so that span contexts can be exchanged with it.
+const (
+	fieldTraceID  = "ot-tracer-traceid"
+	fieldSpanID   = "ot-tracer-spanid"
+	fieldSampled  = "ot-tracer-sampled"
+	prefixBaggage = "ot-baggage-"
+	fieldCount    = 2
+)
+
+// Inject writes span context to text map and http headers carriers
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(spanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return opentracing.ErrUnsupportedFormat
+	}
+	w, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	w.Set(fieldTraceID, formatID(sc.TraceID))
+	w.Set(fieldSpanID, formatID(sc.SpanID))
+	w.Set(fieldSampled, "true")
+	for k, v := range sc.Baggage {
+		w.Set(prefixBaggage+k, v)
+	}
+	return nil
+}
+
+// Extract reads span context from text map and http headers carriers
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	r, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+
+	sc := spanContext{Baggage: map[string]string{}}
+	found := 0
+	err := r.ForeachKey(func(key, value string) error {
+		var err error
+		switch k := strings.ToLower(key); {
+		case k == fieldTraceID:
+			sc.TraceID, err = strconv.ParseUint(value, 16, 64)
+			found++
+		case k == fieldSpanID:
+			sc.SpanID, err = strconv.ParseUint(value, 16, 64)
+			found++
+		case strings.HasPrefix(k, prefixBaggage):
+			sc.Baggage[strings.TrimPrefix(k, prefixBaggage)] = value
+		}
+		if err != nil {
+			return opentracing.ErrSpanContextCorrupted
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == 0 {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	if found < fieldCount {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+	return sc, nil
+}
@@ -0,0 +1,231 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// Record is JSON presentation of finished span. Identifiers
+// are written as hexadecimal strings, parent ID is left
+// out from root spans.
+type Record struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Service    string                 `json:"service,omitempty"`
+	Operation  string                 `json:"operation"`
+	Start      time.Time              `json:"start"`
+	DurationNs int64                  `json:"duration_ns"`
+	Tags       map[string]interface{} `json:"tags,omitempty"`
+	Logs       []LogRecord            `json:"logs,omitempty"`
+	Baggage    map[string]string      `json:"baggage,omitempty"`
+}
+
+// Duration returns duration of span
+func (r *Record) Duration() time.Duration {
+	return time.Duration(r.DurationNs)
+}
+
+// LogRecord is JSON presentation of span log
+type LogRecord struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+// spanContext holds identifiers and baggage of span
+type spanContext struct {
+	TraceID uint64
+	SpanID  uint64
+	Baggage map[string]string
+}
+
+// ForeachBaggageItem implements opentracing.SpanContext
+func (sc spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range sc.Baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+// withBaggageItem returns copy of span context with baggage item set
+func (sc spanContext) withBaggageItem(key, value string) spanContext {
+	baggage := make(map[string]string, len(sc.Baggage)+1)
+	for k, v := range sc.Baggage {
+		baggage[k] = v
+	}
+	baggage[key] = value
+	return spanContext{TraceID: sc.TraceID, SpanID: sc.SpanID, Baggage: baggage}
+}
+
+// span collects tags and logs until it is finished
+type span struct {
+	tracer *Tracer
+
+	mu            sync.Mutex
+	operationName string
+	start         time.Time
+	parentID      uint64
+	context       spanContext
+	tags          map[string]interface{}
+	logs          []LogRecord
+	finished      bool
+}
+
+func (s *span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	finish := opts.FinishTime
+	if finish.IsZero() {
+		finish = time.Now()
+	}
+
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	for _, lr := range opts.LogRecords {
+		s.appendLog(lr.Timestamp, lr.Fields)
+	}
+	for _, ld := range opts.BulkLogData {
+		lr := ld.ToLogRecord()
+		s.appendLog(lr.Timestamp, lr.Fields)
+	}
+	r := &Record{
+		TraceID:    formatID(s.context.TraceID),
+		SpanID:     formatID(s.context.SpanID),
+		Service:    s.tracer.serviceName,
+		Operation:  s.operationName,
+		Start:      s.start,
+		DurationNs: int64(finish.Sub(s.start)),
+		Logs:       s.logs,
+	}
+	if s.parentID != 0 {
+		r.ParentID = formatID(s.parentID)
+	}
+	if len(s.tags) > 0 {
+		r.Tags = make(map[string]interface{}, len(s.tags))
+		for k, v := range s.tags {
+			r.Tags[k] = jsonValue(v)
+		}
+	}
+	if len(s.context.Baggage) > 0 {
+		r.Baggage = s.context.Baggage
+	}
+	s.mu.Unlock()
+
+	s.tracer.write(r)
+}
+
+func (s *span) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operationName = operationName
+	return s
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+	return s
+}
+
+func (s *span) LogFields(fields ...log.Field) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appendLog(time.Now(), fields)
+}
+
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		fields = []log.Field{log.Error(err), log.String("function", "LogKV")}
+	}
+	s.LogFields(fields...)
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.context = s.context.withBaggageItem(restrictedKey, value)
+	return s
+}
+
+func (s *span) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context.Baggage[restrictedKey]
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+func (s *span) LogEvent(event string) {
+	s.Log(opentracing.LogData{Event: event})
+}
+
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	s.Log(opentracing.LogData{Event: event, Payload: payload})
+}
+
+func (s *span) Log(data opentracing.LogData) {
+	lr := data.ToLogRecord()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appendLog(lr.Timestamp, lr.Fields)
+}
+
+// appendLog adds log fields to span, lock must be held
+func (s *span) appendLog(ts time.Time, fields []log.Field) {
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	lr := LogRecord{
+		Timestamp: ts,
+		Fields:    make(map[string]interface{}, len(fields)),
+	}
+	for _, f := range fields {
+		lr.Fields[f.Key()] = jsonValue(f.Value())
+	}
+	s.logs = append(s.logs, lr)
+}
+
+// jsonValue returns value, which can be encoded to JSON.
+// Errors and other values, which can not be encoded are
+// written as strings.
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return value
+	case error:
+		return value.Error()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// formatID formats identifier as hexadecimal string
+func formatID(id uint64) string {
+	return strconv.FormatUint(id, 16)
+}
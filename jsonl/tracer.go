@@ -0,0 +1,136 @@
+// Package jsonl implements tracer, which writes finished spans as JSON
+// lines. It is meant for local development, when there is no tracing
+// agent running, so that traces can be inspected offline e.g. with jq.
+package jsonl
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Tracer writes finished spans to writer as JSON lines.
+// It is safe for concurrent use.
+type Tracer struct {
+	mu          sync.Mutex
+	enc         *json.Encoder
+	closer      io.Closer
+	serviceName string
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// Option typed functions can be used to configure JSON lines tracer
+type Option func(*Tracer)
+
+// WithServiceName gives service name written to every span
+func WithServiceName(name string) Option {
+	return func(t *Tracer) {
+		t.serviceName = name
+	}
+}
+
+// New creates tracer, which writes finished spans to given writer
+func New(w io.Writer, opts ...Option) *Tracer {
+	t := &Tracer{
+		enc:  json.NewEncoder(w),
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewFile creates tracer, which appends finished spans to file in given
+// path. Path "-" writes spans to stdout. File is closed with Close.
+func NewFile(path string, opts ...Option) (*Tracer, error) {
+	if path == "-" {
+		return New(os.Stdout, opts...), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	t := New(f, opts...)
+	t.closer = f
+	return t, nil
+}
+
+// Close closes file opened by NewFile
+func (t *Tracer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closer == nil {
+		return nil
+	}
+	err := t.closer.Close()
+	t.closer = nil
+	return err
+}
+
+// StartSpan starts new span. Span is written when it is finished.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	sso := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&sso)
+	}
+	if sso.StartTime.IsZero() {
+		sso.StartTime = time.Now()
+	}
+
+	s := &span{
+		tracer:        t,
+		operationName: operationName,
+		start:         sso.StartTime,
+		tags:          map[string]interface{}{},
+		context: spanContext{
+			SpanID:  t.randomID(),
+			Baggage: map[string]string{},
+		},
+	}
+	for k, v := range sso.Tags {
+		s.tags[k] = v
+	}
+	for _, ref := range sso.References {
+		sc, ok := ref.ReferencedContext.(spanContext)
+		if !ok {
+			continue
+		}
+		if s.context.TraceID == 0 {
+			s.context.TraceID = sc.TraceID
+			s.parentID = sc.SpanID
+		}
+		for k, v := range sc.Baggage {
+			s.context.Baggage[k] = v
+		}
+	}
+	if s.context.TraceID == 0 {
+		s.context.TraceID = t.randomID()
+	}
+	return s
+}
+
+// randomID returns non-zero random identifier
+func (t *Tracer) randomID() uint64 {
+	t.randMu.Lock()
+	defer t.randMu.Unlock()
+	for {
+		if id := t.rand.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
+
+// write writes record of finished span as single line
+func (t *Tracer) write(r *Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enc.Encode(r)
+}
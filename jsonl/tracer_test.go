@@ -0,0 +1,140 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+func records(t *testing.T, b []byte) []Record {
+	t.Helper()
+	rs := []Record{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		r := Record{}
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			t.Fatalf("invalid JSON line '%s': %v", s.Text(), err)
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func TestTracerWrite(t *testing.T) {
+	b := &bytes.Buffer{}
+	tracer := New(b, WithServiceName("orders"))
+
+	start := time.Now()
+	root := tracer.StartSpan("GET /orders", opentracing.StartTime(start), ext.SpanKindRPCServer)
+	root.SetBaggageItem("tenant_id", "42")
+	child := tracer.StartSpan("sql.query", opentracing.ChildOf(root.Context()))
+	child.SetTag("rows", 3)
+	child.SetTag("ratio", math.NaN())
+	child.LogFields(log.Error(errors.New("deadlock")), log.String("event", "error"))
+	child.Finish()
+	root.FinishWithOptions(opentracing.FinishOptions{FinishTime: start.Add(time.Second)})
+
+	rs := records(t, b.Bytes())
+	if len(rs) != 2 {
+		t.Fatalf("incorrect number of lines: %d", len(rs))
+	}
+	c, r := rs[0], rs[1]
+	if r.Service != "orders" || r.Operation != "GET /orders" || r.ParentID != "" {
+		t.Errorf("incorrect root span: %+v", r)
+	}
+	if r.Duration() != time.Second {
+		t.Errorf("incorrect duration: %v", r.Duration())
+	}
+	if r.Tags["span.kind"] != "server" || r.Baggage["tenant_id"] != "42" {
+		t.Errorf("incorrect root tags or baggage: %+v", r)
+	}
+	if c.TraceID != r.TraceID || c.ParentID != r.SpanID {
+		t.Errorf("child is not child of root: %+v", c)
+	}
+	if c.Baggage["tenant_id"] != "42" {
+		t.Errorf("baggage not propagated to child")
+	}
+	if c.Tags["rows"] != float64(3) || c.Tags["ratio"] != "NaN" {
+		t.Errorf("incorrect child tags: %v", c.Tags)
+	}
+	if len(c.Logs) != 1 || c.Logs[0].Fields["error"] != "deadlock" {
+		t.Errorf("incorrect child logs: %v", c.Logs)
+	}
+}
+
+func TestTracerInjectExtract(t *testing.T) {
+	tracer := New(ioutil.Discard)
+	span := tracer.StartSpan("root")
+	span.SetBaggageItem("tenant_id", "42")
+
+	h := http.Header{}
+	if err := tracer.Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+		t.Fatalf("unexpected inject error: %v", err)
+	}
+	sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatalf("unexpected extract error: %v", err)
+	}
+	expected := span.Context().(spanContext)
+	actual := sc.(spanContext)
+	if actual.TraceID != expected.TraceID || actual.SpanID != expected.SpanID || actual.Baggage["tenant_id"] != "42" {
+		t.Errorf("incorrect span context: %+v, expected: %+v", actual, expected)
+	}
+
+	tests := []struct {
+		name    string
+		carrier opentracing.TextMapCarrier
+		err     error
+	}{
+		{"not found", opentracing.TextMapCarrier{}, opentracing.ErrSpanContextNotFound},
+		{"missing span ID", opentracing.TextMapCarrier{fieldTraceID: "1"}, opentracing.ErrSpanContextCorrupted},
+		{"invalid ID", opentracing.TextMapCarrier{fieldTraceID: "x", fieldSpanID: "1"}, opentracing.ErrSpanContextCorrupted},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := tracer.Extract(opentracing.TextMap, test.carrier); err != test.err {
+				t.Errorf("incorrect error: %v, expected: %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.jsonl")
+
+	for i := 0; i < 2; i++ {
+		tracer, err := NewFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tracer.StartSpan("root").Finish()
+		if err := tracer.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs := records(t, b); len(rs) != 2 {
+		t.Errorf("spans not appended to file: %d", len(rs))
+	}
+}
@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"github.com/segmentio/kafka-go"
+	"github.com/streadway/amqp"
+)
+
+// MapCarrier is opentracing.TextMapWriter and opentracing.TextMapReader
+// over string map, such as message attributes of queues
+type MapCarrier map[string]string
+
+// Set sets value of key
+func (c MapCarrier) Set(key, val string) {
+	c[key] = val
+}
+
+// ForeachKey calls handler for every key of map
+func (c MapCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KafkaHeadersCarrier is opentracing.TextMapWriter and
+// opentracing.TextMapReader over Kafka message headers
+type KafkaHeadersCarrier []kafka.Header
+
+// KafkaHeaders returns carrier, which modifies given headers
+func KafkaHeaders(headers *[]kafka.Header) *KafkaHeadersCarrier {
+	return (*KafkaHeadersCarrier)(headers)
+}
+
+// Set replaces value of existing header or appends new header
+func (c *KafkaHeadersCarrier) Set(key, val string) {
+	for i, h := range *c {
+		if h.Key == key {
+			(*c)[i].Value = []byte(val)
+			return
+		}
+	}
+	*c = append(*c, kafka.Header{Key: key, Value: []byte(val)})
+}
+
+// ForeachKey calls handler for every header
+func (c KafkaHeadersCarrier) ForeachKey(handler func(key, val string) error) error {
+	for _, h := range c {
+		if err := handler(h.Key, string(h.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TableCarrier is opentracing.TextMapWriter and
+// opentracing.TextMapReader over AMQP headers table
+type TableCarrier amqp.Table
+
+// Set sets value of header
+func (c TableCarrier) Set(key, val string) {
+	c[key] = val
+}
+
+// ForeachKey calls handler for every header with string
+// or byte slice value. Other headers are skipped.
+func (c TableCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		var s string
+		switch value := v.(type) {
+		case string:
+			s = value
+		case []byte:
+			s = string(value)
+		default:
+			continue
+		}
+		if err := handler(k, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Package messaging implements carriers over message headers and helpers
+// to start producer and consumer spans, so that trace context is
+// propagated through message brokers. Consumer spans follow from
+// producer spans, as producers do not wait for messages to be handled.
+package messaging
+
+import (
+	"context"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	ddext "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+)
+
+// StartProducerSpan starts producer span for message sent to given
+// destination and injects its span context to carrier of message.
+// Span is child of span in context or new root span.
+func StartProducerSpan(ctx context.Context, operationName, destination string, carrier opentracing.TextMapWriter, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	opts = append(startSpanOptions(destination, ddext.SpanTypeMessageProducer), opts...)
+	opts = append(opts, ext.SpanKindProducer)
+	span, ctx := utils.StartSpanFromContext(ctx, operationName, opts...)
+	opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, carrier)
+	return span, ctx
+}
+
+// StartConsumerSpan starts consumer span for message received from given
+// destination. Span follows from span context extracted from carrier of
+// message. If message has no span context, span is child of span in
+// context or new root span.
+func StartConsumerSpan(ctx context.Context, operationName, destination string, carrier opentracing.TextMapReader, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	opts = append(startSpanOptions(destination, ddext.SpanTypeMessageConsumer), opts...)
+	opts = append(opts, ext.SpanKindConsumer)
+	sc, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, carrier)
+	if err != nil {
+		return utils.StartSpanFromContext(ctx, operationName, opts...)
+	}
+
+	span := opentracing.StartSpan(operationName, append(opts, opentracing.FollowsFrom(sc))...)
+	utils.PromoteContextBaggage(ctx, span)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// startSpanOptions returns options common to producer and consumer spans
+func startSpanOptions(destination, spanType string) []opentracing.StartSpanOption {
+	opts := []opentracing.StartSpanOption{opentracer.SpanTypeOption(spanType)}
+	if destination != "" {
+		opts = append(opts,
+			opentracing.Tag{Key: string(ext.MessageBusDestination), Value: destination},
+			opentracer.ResourceOption(destination))
+	}
+	return opts
+}
@@ -0,0 +1,157 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer"
+	"github.com/foodiefm/opentracing/contrib/gopkg.in/DataDog/dd-trace-go.v1/ddtrace/opentracer/fakeagent"
+	"github.com/foodiefm/opentracing/tracetest"
+	"github.com/foodiefm/opentracing/utils"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/segmentio/kafka-go"
+	"github.com/streadway/amqp"
+	ddtracer "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+func TestCarriers(t *testing.T) {
+	kafkaHeaders := []kafka.Header{{Key: "content-type", Value: []byte("application/json")}}
+	table := amqp.Table{"retries": int32(2)}
+
+	tests := []struct {
+		name    string
+		writer  opentracing.TextMapWriter
+		reader  func() opentracing.TextMapReader
+		entries int
+	}{
+		{"map", MapCarrier{}, nil, 0},
+		{"kafka headers", KafkaHeaders(&kafkaHeaders), func() opentracing.TextMapReader { return KafkaHeadersCarrier(kafkaHeaders) }, 1},
+		{"amqp table", TableCarrier(table), nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.writer.Set("trace-id", "1")
+			tt.writer.Set("trace-id", "2")
+			tt.writer.Set("baggage-tenant", "42")
+
+			reader, ok := tt.writer.(opentracing.TextMapReader)
+			if tt.reader != nil {
+				reader = tt.reader()
+			} else if !ok {
+				t.Fatalf("carrier is not reader")
+			}
+			values := map[string]string{}
+			reader.ForeachKey(func(k, v string) error {
+				values[k] = v
+				return nil
+			})
+			if len(values) != tt.entries+2 || values["trace-id"] != "2" || values["baggage-tenant"] != "42" {
+				t.Errorf("incorrect values: %v", values)
+			}
+		})
+	}
+}
+
+func TestProducerConsumerSpans(t *testing.T) {
+	recorder, done := tracetest.Global()
+	defer done()
+
+	root, ctx := opentracing.StartSpanFromContext(context.Background(), "root")
+	root.SetBaggageItem("tenant_id", "42")
+	headers := []kafka.Header{}
+	ctx = utils.ContextWithBaggageTags(ctx, "tenant_id")
+	producer, _ := StartProducerSpan(ctx, "kafka.produce", "orders", KafkaHeaders(&headers))
+	producer.Finish()
+	root.Finish()
+
+	consumer, cctx := StartConsumerSpan(utils.ContextWithBaggageTags(context.Background(), "tenant_id"), "kafka.consume", "orders", KafkaHeadersCarrier(headers))
+	if opentracing.SpanFromContext(cctx) != consumer {
+		t.Errorf("consumer span not in context")
+	}
+	if consumer.BaggageItem("tenant_id") != "42" {
+		t.Errorf("baggage not propagated in message")
+	}
+	consumer.Finish()
+
+	p := recorder.RequireSpan(t, "kafka.produce")
+	c := recorder.RequireSpan(t, "kafka.consume")
+	tracetest.AssertChildOf(t, p, recorder.RequireSpan(t, "root"))
+	tracetest.AssertTags(t, p, map[string]interface{}{
+		"span.kind":               "producer",
+		"message_bus.destination": "orders",
+		"resource.name":           "orders",
+		"span.type":               "queue",
+		"tenant_id":               "42",
+	})
+	tracetest.AssertTags(t, c, map[string]interface{}{
+		"span.kind":               "consumer",
+		"message_bus.destination": "orders",
+		"tenant_id":               "42",
+	})
+	if c.ParentID != p.SpanContext.SpanID || c.SpanContext.TraceID != p.SpanContext.TraceID {
+		t.Errorf("consumer span does not follow from producer span")
+	}
+}
+
+func TestConsumerSpanAgent(t *testing.T) {
+	agent := fakeagent.New()
+	defer agent.Close()
+	tr := opentracer.NewTracer("orders", opentracer.WithStartOptions(ddtracer.WithAgentAddr(agent.Addr())))
+	defer opentracing.SetGlobalTracer(opentracing.GlobalTracer())
+	opentracing.SetGlobalTracer(tr)
+
+	headers := []kafka.Header{}
+	producer, _ := StartProducerSpan(context.Background(), "kafka.produce", "orders", KafkaHeaders(&headers))
+	producer.Finish()
+	consumer, _ := StartConsumerSpan(context.Background(), "kafka.consume", "orders", KafkaHeadersCarrier(headers))
+	consumer.Finish()
+	tr.Close()
+
+	spans := map[string]fakeagent.Span{}
+	for _, span := range agent.Spans() {
+		spans[span.Meta["span.kind"]] = span
+	}
+	p, c := spans["producer"], spans["consumer"]
+	if p.SpanID == 0 || c.SpanID == 0 {
+		t.Fatalf("spans not sent to agent: %v", spans)
+	}
+	if c.TraceID != p.TraceID || c.ParentID != p.SpanID {
+		t.Errorf("consumer span not in trace of producer span: producer %+v, consumer %+v", p, c)
+	}
+}
+
+func TestConsumerSpanWithoutContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent bool
+	}{
+		{"root span", false},
+		{"child of context span", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, done := tracetest.Global()
+			defer done()
+
+			ctx := context.Background()
+			var worker opentracing.Span
+			if tt.parent {
+				worker, ctx = opentracing.StartSpanFromContext(ctx, "worker")
+			}
+			consumer, _ := StartConsumerSpan(ctx, "sqs.receive", "", MapCarrier{})
+			consumer.Finish()
+
+			parentID := consumer.(*mocktracer.MockSpan).ParentID
+			if tt.parent && parentID != worker.Context().(mocktracer.MockSpanContext).SpanID {
+				t.Errorf("consumer span is not child of context span")
+			}
+			if !tt.parent && parentID != 0 {
+				t.Errorf("consumer span is not root span")
+			}
+			if recorder.RequireSpan(t, "sqs.receive").Tag("message_bus.destination") != nil {
+				t.Errorf("empty destination tagged")
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Package tee implements tracer, which sends same spans to multiple
+// tracers. It can be used as global tracer, when spans are sent to
+// two backends at once e.g. during migration from one to another.
+package tee
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// Tracer starts spans in all wrapped tracers. First tracer is primary,
+// which span context is injected to carriers. Span contexts of other
+// tracers are injected only if secondary inject is enabled.
+type Tracer struct {
+	tracers         []opentracing.Tracer
+	secondaryInject bool
+}
+
+// Option typed functions can be used to configure tee tracer
+type Option func(*Tracer)
+
+// WithSecondaryInject injects span contexts of secondary tracers to carriers
+// in addition to primary span context. Tracers must use different keys in
+// carriers, as otherwise secondary tracers overwrite primary span context.
+func WithSecondaryInject() Option {
+	return func(t *Tracer) {
+		t.secondaryInject = true
+	}
+}
+
+// New creates tracer, which sends spans to all given tracers.
+// First of tracers is used as primary tracer. New panics,
+// if no tracers are given.
+func New(tracers []opentracing.Tracer, opts ...Option) *Tracer {
+	if len(tracers) == 0 {
+		panic("tee: no tracers given")
+	}
+	t := &Tracer{
+		tracers: tracers,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// StartSpan starts span in all tracers. References to span contexts of tee
+// tracer are mapped to span contexts of each tracer. Other references are
+// given only to primary tracer.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	sso := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&sso)
+	}
+
+	s := &span{
+		tracer: t,
+		spans:  make([]opentracing.Span, len(t.tracers)),
+	}
+	for i, tracer := range t.tracers {
+		s.spans[i] = tracer.StartSpan(operationName, t.startSpanOptions(i, &sso)...)
+	}
+	return s
+}
+
+// startSpanOptions returns start options for tracer with given index
+func (t *Tracer) startSpanOptions(i int, sso *opentracing.StartSpanOptions) []opentracing.StartSpanOption {
+	opts := []opentracing.StartSpanOption{}
+	if !sso.StartTime.IsZero() {
+		opts = append(opts, opentracing.StartTime(sso.StartTime))
+	}
+	if len(sso.Tags) > 0 {
+		tags := opentracing.Tags{}
+		for k, v := range sso.Tags {
+			tags[k] = v
+		}
+		opts = append(opts, tags)
+	}
+	for _, ref := range sso.References {
+		switch sc := ref.ReferencedContext.(type) {
+		case spanContext:
+			if sc.context(i) != nil {
+				opts = append(opts, opentracing.SpanReference{Type: ref.Type, ReferencedContext: sc.context(i)})
+			}
+		default:
+			if i == 0 && sc != nil {
+				opts = append(opts, ref)
+			}
+		}
+	}
+	return opts
+}
+
+// Inject injects primary span context to carrier and if secondary
+// inject is enabled also span contexts of secondary tracers.
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(spanContext)
+	if !ok {
+		return t.tracers[0].Inject(sm, format, carrier)
+	}
+
+	var err error
+	for i, tracer := range t.tracers {
+		if i > 0 && !t.secondaryInject {
+			break
+		}
+		if sc.context(i) == nil {
+			continue
+		}
+		if ierr := tracer.Inject(sc.context(i), format, carrier); ierr != nil && err == nil {
+			err = ierr
+		}
+	}
+	return err
+}
+
+// Extract extracts span context with all tracers. Span context is
+// returned, if any of tracers succeeds, otherwise error of primary
+// tracer is returned.
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	sc := spanContext{
+		contexts: make([]opentracing.SpanContext, len(t.tracers)),
+	}
+	var err error
+	found := false
+	for i, tracer := range t.tracers {
+		ctx, eerr := tracer.Extract(format, carrier)
+		if eerr != nil {
+			if i == 0 {
+				err = eerr
+			}
+			continue
+		}
+		sc.contexts[i] = ctx
+		found = true
+	}
+	if !found {
+		if err == nil {
+			err = opentracing.ErrSpanContextNotFound
+		}
+		return nil, err
+	}
+	return sc, nil
+}
+
+// spanContext holds span contexts of all tracers. Context is nil
+// for tracers, which could not extract span context.
+type spanContext struct {
+	contexts []opentracing.SpanContext
+}
+
+// context returns span context of tracer with given index. Nil is returned,
+// if span context is from tee tracer with fewer tracers.
+func (sc spanContext) context(i int) opentracing.SpanContext {
+	if i >= len(sc.contexts) {
+		return nil
+	}
+	return sc.contexts[i]
+}
+
+// ForeachBaggageItem iterates baggage items of first available span context
+func (sc spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for _, ctx := range sc.contexts {
+		if ctx != nil {
+			ctx.ForeachBaggageItem(handler)
+			return
+		}
+	}
+}
+
+// span forwards calls to spans of all tracers
+type span struct {
+	tracer *Tracer
+	spans  []opentracing.Span
+}
+
+func (s *span) Finish() {
+	for _, span := range s.spans {
+		span.Finish()
+	}
+}
+
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	for _, span := range s.spans {
+		span.FinishWithOptions(opts)
+	}
+}
+
+func (s *span) Context() opentracing.SpanContext {
+	sc := spanContext{
+		contexts: make([]opentracing.SpanContext, len(s.spans)),
+	}
+	for i, span := range s.spans {
+		sc.contexts[i] = span.Context()
+	}
+	return sc
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	for _, span := range s.spans {
+		span.SetOperationName(operationName)
+	}
+	return s
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	for _, span := range s.spans {
+		span.SetTag(key, value)
+	}
+	return s
+}
+
+func (s *span) LogFields(fields ...log.Field) {
+	for _, span := range s.spans {
+		span.LogFields(fields...)
+	}
+}
+
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	for _, span := range s.spans {
+		span.LogKV(alternatingKeyValues...)
+	}
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	for _, span := range s.spans {
+		span.SetBaggageItem(restrictedKey, value)
+	}
+	return s
+}
+
+// BaggageItem returns baggage item of primary span
+func (s *span) BaggageItem(restrictedKey string) string {
+	return s.spans[0].BaggageItem(restrictedKey)
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+func (s *span) LogEvent(event string) {
+	for _, span := range s.spans {
+		span.LogEvent(event)
+	}
+}
+
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	for _, span := range s.spans {
+		span.LogEventWithPayload(event, payload)
+	}
+}
+
+func (s *span) Log(data opentracing.LogData) {
+	for _, span := range s.spans {
+		span.Log(data)
+	}
+}
@@ -0,0 +1,173 @@
+package tee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	wrapping "github.com/foodiefm/opentracing/contrib/net/http"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestTracerStartSpan(t *testing.T) {
+	primary, secondary := mocktracer.New(), mocktracer.New()
+	tracer := New([]opentracing.Tracer{primary, secondary})
+
+	root := tracer.StartSpan("root", opentracing.Tag{Key: "component", Value: "test"})
+	root.SetBaggageItem("tenant_id", "42")
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.SetTag("order_id", 1)
+	child.SetOperationName("renamed")
+	child.Finish()
+	root.Finish()
+
+	if child.BaggageItem("tenant_id") != "42" {
+		t.Errorf("baggage not propagated to child")
+	}
+	for _, mt := range []*mocktracer.MockTracer{primary, secondary} {
+		spans := mt.FinishedSpans()
+		if len(spans) != 2 {
+			t.Fatalf("incorrect number of spans: %d", len(spans))
+		}
+		if spans[0].OperationName != "renamed" || spans[0].Tag("order_id") != 1 {
+			t.Errorf("child span not updated")
+		}
+		if spans[1].Tag("component") != "test" {
+			t.Errorf("start tags not given to tracer")
+		}
+		if spans[0].ParentID != spans[1].SpanContext.SpanID {
+			t.Errorf("child span is not child of root in its tracer")
+		}
+	}
+}
+
+func TestTracerForeignReference(t *testing.T) {
+	primary, secondary := mocktracer.New(), mocktracer.New()
+	tracer := New([]opentracing.Tracer{primary, secondary})
+
+	parent := primary.StartSpan("parent")
+	span := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()))
+	span.Finish()
+	parent.Finish()
+
+	if spans := primary.FinishedSpans(); spans[0].ParentID != spans[1].SpanContext.SpanID {
+		t.Errorf("reference not given to primary tracer")
+	}
+	if spans := secondary.FinishedSpans(); spans[0].ParentID != 0 {
+		t.Errorf("foreign reference given to secondary tracer")
+	}
+}
+
+func TestTracerFewerContexts(t *testing.T) {
+	primary, secondary := mocktracer.New(), mocktracer.New()
+	single := New([]opentracing.Tracer{primary})
+	tracer := New([]opentracing.Tracer{primary, secondary}, WithSecondaryInject())
+
+	parent := single.StartSpan("parent")
+	span := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()))
+	span.Finish()
+	parent.Finish()
+
+	if spans := primary.FinishedSpans(); spans[0].ParentID != spans[1].SpanContext.SpanID {
+		t.Errorf("reference not given to primary tracer")
+	}
+	if spans := secondary.FinishedSpans(); len(spans) != 1 || spans[0].ParentID != 0 {
+		t.Errorf("secondary span has parent")
+	}
+	if err := tracer.Inject(parent.Context(), opentracing.TextMap, opentracing.TextMapCarrier{}); err != nil {
+		t.Errorf("unexpected inject error: %v", err)
+	}
+}
+
+func TestNewWithoutTracers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("New did not panic without tracers")
+		}
+	}()
+	New(nil)
+}
+
+func TestTracerInjectExtract(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []Option
+		secondaryInject bool
+	}{
+		{"primary inject", nil, false},
+		{"secondary inject", []Option{WithSecondaryInject()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, secondary := mocktracer.New(), mocktracer.New()
+			injected := 0
+			secondary.RegisterInjector(opentracing.HTTPHeaders, injectorFunc(func(sc mocktracer.MockSpanContext, carrier interface{}) error {
+				injected++
+				return nil
+			}))
+			tracer := New([]opentracing.Tracer{primary, secondary}, tt.opts...)
+
+			root := tracer.StartSpan("root")
+			h := http.Header{}
+			if err := tracer.Inject(root.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h)); err != nil {
+				t.Fatalf("unexpected inject error: %v", err)
+			}
+			if (injected > 0) != tt.secondaryInject {
+				t.Errorf("secondary inject = %v, want %v", injected > 0, tt.secondaryInject)
+			}
+
+			sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+			if err != nil {
+				t.Fatalf("unexpected extract error: %v", err)
+			}
+			primaryID := root.(*span).spans[0].Context().(mocktracer.MockSpanContext).SpanID
+			contexts := sc.(spanContext).contexts
+			if contexts[0] == nil || contexts[0].(mocktracer.MockSpanContext).SpanID != primaryID {
+				t.Errorf("primary span context not extracted")
+			}
+		})
+	}
+}
+
+func TestTracerExtractNotFound(t *testing.T) {
+	tracer := New([]opentracing.Tracer{mocktracer.New(), mocktracer.New()})
+	_, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{}))
+	if err != opentracing.ErrSpanContextNotFound {
+		t.Errorf("expected span context not found error, got %v", err)
+	}
+}
+
+func TestTracerWithClient(t *testing.T) {
+	primary, secondary := mocktracer.New(), mocktracer.New()
+	opentracing.SetGlobalTracer(New([]opentracing.Tracer{primary, secondary}))
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := opentracing.GlobalTracer().Extract(
+			opentracing.HTTPHeaders,
+			opentracing.HTTPHeadersCarrier(r.Header)); err != nil {
+			t.Errorf("There is no span in request")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := wrapping.WrapClient(server.Client(), "")
+	span := opentracing.StartSpan("root")
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/test", nil)
+	client.Do(req.WithContext(opentracing.ContextWithSpan(context.Background(), span)))
+	span.Finish()
+
+	if len(primary.FinishedSpans()) != 2 || len(secondary.FinishedSpans()) != 2 {
+		t.Errorf("spans not sent to both tracers")
+	}
+}
+
+// injectorFunc implements mocktracer.Injector
+type injectorFunc func(mocktracer.MockSpanContext, interface{}) error
+
+func (f injectorFunc) Inject(sc mocktracer.MockSpanContext, carrier interface{}) error {
+	return f(sc, carrier)
+}
@@ -0,0 +1,41 @@
+package tracetest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites golden files instead of comparing to them, when tests
+// are run with -tracetest.update flag
+var update = flag.Bool("tracetest.update", false, "update span tree golden files")
+
+// AssertGolden compares span tree of recorder to golden file. Golden
+// file is created or updated, when tests are run with -tracetest.update
+// flag. Tags with given keys are left out of span tree.
+func (r *Recorder) AssertGolden(t testing.TB, path string, ignore ...string) bool {
+	t.Helper()
+	tree := r.Tree(ignore...)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden file directory failed: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(tree), 0644); err != nil {
+			t.Fatalf("writing golden file failed: %v", err)
+		}
+		return true
+	}
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file failed: %v, run tests with -tracetest.update to create it", err)
+	}
+	if string(golden) != tree {
+		t.Errorf("span tree does not match golden file %s\nexpected:\n%s\ngot:\n%s", path, golden, tree)
+		return false
+	}
+	return true
+}
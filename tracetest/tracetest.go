@@ -0,0 +1,196 @@
+// Package tracetest implements in-memory span recorder and
+// assertion helpers for testing traced code.
+package tracetest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+// Recorder is tracer, which records finished spans in memory.
+// It is safe for concurrent use.
+type Recorder struct {
+	*mocktracer.MockTracer
+}
+
+// NewRecorder creates new span recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		MockTracer: mocktracer.New(),
+	}
+}
+
+// globalMu serializes tests, which use global tracer
+var globalMu sync.Mutex
+
+// Global creates recorder and sets it as global tracer. Returned function
+// restores noop tracer and it must be called when test ends. Tests using
+// global tracer are run one at a time, so they can be marked parallel.
+func Global() (*Recorder, func()) {
+	globalMu.Lock()
+	r := NewRecorder()
+	opentracing.SetGlobalTracer(r)
+	return r, func() {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		globalMu.Unlock()
+	}
+}
+
+// FindSpans returns finished spans with given operation name
+func (r *Recorder) FindSpans(operationName string) []*mocktracer.MockSpan {
+	spans := []*mocktracer.MockSpan{}
+	for _, span := range r.FinishedSpans() {
+		if span.OperationName == operationName {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// FindSpan returns first finished span with given operation name
+// or nil, if there is no such span
+func (r *Recorder) FindSpan(operationName string) *mocktracer.MockSpan {
+	if spans := r.FindSpans(operationName); len(spans) > 0 {
+		return spans[0]
+	}
+	return nil
+}
+
+// FindSpansByTag returns finished spans, which have tag with given value
+func (r *Recorder) FindSpansByTag(key string, value interface{}) []*mocktracer.MockSpan {
+	spans := []*mocktracer.MockSpan{}
+	for _, span := range r.FinishedSpans() {
+		if v, ok := span.Tags()[key]; ok && fmt.Sprint(v) == fmt.Sprint(value) {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// RequireSpan returns first finished span with given operation
+// name and fails test immediately, if there is no such span
+func (r *Recorder) RequireSpan(t testing.TB, operationName string) *mocktracer.MockSpan {
+	t.Helper()
+	span := r.FindSpan(operationName)
+	if span == nil {
+		t.Fatalf("span '%s' not found, finished spans:\n%s", operationName, r.Tree())
+	}
+	return span
+}
+
+// AssertSpanCount checks number of finished spans
+func (r *Recorder) AssertSpanCount(t testing.TB, count int) bool {
+	t.Helper()
+	if n := len(r.FinishedSpans()); n != count {
+		t.Errorf("incorrect number of spans: %d, expected: %d\n%s", n, count, r.Tree())
+		return false
+	}
+	return true
+}
+
+// AssertChildOf checks that child span is direct child of parent span
+func AssertChildOf(t testing.TB, child, parent *mocktracer.MockSpan) bool {
+	t.Helper()
+	if child.ParentID != parent.SpanContext.SpanID || child.SpanContext.TraceID != parent.SpanContext.TraceID {
+		t.Errorf("span '%s' is not child of span '%s'", child.OperationName, parent.OperationName)
+		return false
+	}
+	return true
+}
+
+// AssertTags checks that span has given tags. Values are compared
+// with their string presentation, so that e.g. uint16 status code
+// can be compared to int.
+func AssertTags(t testing.TB, span *mocktracer.MockSpan, tags map[string]interface{}) bool {
+	t.Helper()
+	ok := true
+	actual := span.Tags()
+	for k, v := range tags {
+		a, exists := actual[k]
+		if !exists {
+			t.Errorf("span '%s' does not have tag '%s'", span.OperationName, k)
+			ok = false
+			continue
+		}
+		if fmt.Sprint(a) != fmt.Sprint(v) {
+			t.Errorf("span '%s' tag '%s' = '%v', expected: '%v'", span.OperationName, k, a, v)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// AssertError checks error flag of span
+func AssertError(t testing.TB, span *mocktracer.MockSpan, expected bool) bool {
+	t.Helper()
+	flag, _ := span.Tag(string(ext.Error)).(bool)
+	if flag != expected {
+		t.Errorf("span '%s' error flag = %v, expected: %v", span.OperationName, flag, expected)
+		return false
+	}
+	return true
+}
+
+// Tree returns finished spans as indented tree of operation names and
+// sorted tags. Children are ordered by their start time. Tags with
+// given keys are left out, so that tree can be compared even if some
+// tag values change between test runs.
+func (r *Recorder) Tree(ignore ...string) string {
+	spans := r.FinishedSpans()
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].StartTime.Before(spans[j].StartTime)
+	})
+
+	ids := map[int]bool{}
+	for _, span := range spans {
+		ids[span.SpanContext.SpanID] = true
+	}
+	children := map[int][]*mocktracer.MockSpan{}
+	roots := []*mocktracer.MockSpan{}
+	for _, span := range spans {
+		if span.ParentID != 0 && ids[span.ParentID] {
+			children[span.ParentID] = append(children[span.ParentID], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	ignored := map[string]bool{}
+	for _, key := range ignore {
+		ignored[key] = true
+	}
+
+	b := &strings.Builder{}
+	var write func(span *mocktracer.MockSpan, depth int)
+	write = func(span *mocktracer.MockSpan, depth int) {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(span.OperationName)
+		tags := span.Tags()
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			if !ignored[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(b, " %s=%v", k, tags[k])
+		}
+		b.WriteString("\n")
+		for _, child := range children[span.SpanContext.SpanID] {
+			write(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		write(root, 0)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package tracetest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// fakeT records failures instead of failing test
+type fakeT struct {
+	*testing.T
+	failures []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func record(r *Recorder) {
+	root := r.StartSpan("GET__orders", ext.SpanKindRPCServer)
+	ext.HTTPStatusCode.Set(root, uint16(500))
+	ext.Error.Set(root, true)
+	first := r.StartSpan("sql.query", opentracing.ChildOf(root.Context()), opentracing.Tag{Key: "db.type", Value: "postgres"})
+	first.Finish()
+	second := r.StartSpan("http.request", opentracing.ChildOf(root.Context()))
+	second.SetTag("order_id", 42)
+	second.Finish()
+	root.Finish()
+}
+
+func TestRecorderFind(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder()
+	record(r)
+
+	if !r.AssertSpanCount(t, 3) {
+		return
+	}
+	root := r.RequireSpan(t, "GET__orders")
+	if len(r.FindSpans("http.request")) != 1 || r.FindSpan("missing") != nil {
+		t.Errorf("spans not found by name")
+	}
+	if spans := r.FindSpansByTag("order_id", "42"); len(spans) != 1 {
+		t.Errorf("span not found by tag")
+	}
+	AssertChildOf(t, r.FindSpan("sql.query"), root)
+	AssertTags(t, root, map[string]interface{}{"http.status_code": 500, "span.kind": "server"})
+	AssertError(t, root, true)
+	AssertError(t, r.FindSpan("sql.query"), false)
+}
+
+func TestAssertFailures(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder()
+	record(r)
+
+	ft := &fakeT{T: t}
+	root := r.FindSpan("GET__orders")
+	child := r.FindSpan("sql.query")
+	if AssertChildOf(ft, root, child) ||
+		AssertTags(ft, root, map[string]interface{}{"http.status_code": 200, "missing": 1}) ||
+		AssertError(ft, child, true) ||
+		r.AssertSpanCount(ft, 1) {
+		t.Errorf("assertions should fail")
+	}
+	if len(ft.failures) != 5 {
+		t.Errorf("incorrect number of failures: %v", ft.failures)
+	}
+}
+
+func TestRecorderTree(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder()
+	record(r)
+
+	expected := "GET__orders error=true span.kind=server\n" +
+		"  sql.query db.type=postgres\n" +
+		"  http.request order_id=42\n"
+	if tree := r.Tree("http.status_code"); tree != expected {
+		t.Errorf("incorrect tree:\n%s", tree)
+	}
+	r.AssertGolden(t, "testdata/tree.golden")
+}
+
+func TestGlobal(t *testing.T) {
+	t.Parallel()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, done := Global()
+			defer done()
+			opentracing.StartSpan("global").Finish()
+			r.AssertSpanCount(t, 1)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// DefaultBaggageKeyPrefix is carrier key prefix of baggage
+// items injected by Datadog and JSON lines tracers
+const DefaultBaggageKeyPrefix = "ot-baggage-"
+
+// BaggageLimits restricts number and size of baggage items, so that
+// baggage does not bloat headers of outgoing requests. Zero value
+// of a field means that it is not limited.
+type BaggageLimits struct {
+	// MaxItems is maximum number of baggage items
+	MaxItems int
+	// MaxItemSize is maximum combined length of single item key and value
+	MaxItemSize int
+	// MaxTotalSize is maximum combined length of all keys and values
+	MaxTotalSize int
+	// KeyPrefix is carrier key prefix, which tracer in use adds to baggage
+	// keys when it injects span context. DefaultBaggageKeyPrefix is used,
+	// if prefix is empty.
+	KeyPrefix string
+}
+
+// SetBaggageItem sets baggage item to span, if it fits inside limits.
+// Returns false, if item was not set.
+func (l BaggageLimits) SetBaggageItem(span opentracing.Span, key, value string) bool {
+	if span == nil || key == "" {
+		return false
+	}
+	size := len(key) + len(value)
+	if l.MaxItemSize > 0 && size > l.MaxItemSize {
+		return false
+	}
+
+	count, total := 0, 0
+	span.Context().ForeachBaggageItem(func(k, v string) bool {
+		if k != key {
+			count++
+			total += len(k) + len(v)
+		}
+		return true
+	})
+	if l.MaxItems > 0 && count+1 > l.MaxItems {
+		return false
+	}
+	if l.MaxTotalSize > 0 && total+size > l.MaxTotalSize {
+		return false
+	}
+
+	span.SetBaggageItem(key, value)
+	return true
+}
+
+// Exceeding returns baggage items of span context, which don't fit inside
+// limits. Items are accepted in key order, so result is stable.
+func (l BaggageLimits) Exceeding(sc opentracing.SpanContext) map[string]string {
+	if sc == nil {
+		return nil
+	}
+	items := map[string]string{}
+	keys := []string{}
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		items[k] = v
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	exceeding := map[string]string{}
+	count, total := 0, 0
+	for _, k := range keys {
+		size := len(k) + len(items[k])
+		if (l.MaxItemSize > 0 && size > l.MaxItemSize) ||
+			(l.MaxItems > 0 && count+1 > l.MaxItems) ||
+			(l.MaxTotalSize > 0 && total+size > l.MaxTotalSize) {
+			exceeding[k] = items[k]
+			continue
+		}
+		count++
+		total += size
+	}
+
+	return exceeding
+}
+
+// Writer wraps carrier so that baggage items of span context exceeding
+// limits are not written to it when span context is injected. Items are
+// recognized by carrier key, which is key prefix and baggage key.
+func (l BaggageLimits) Writer(sc opentracing.SpanContext, w opentracing.TextMapWriter) opentracing.TextMapWriter {
+	exceeding := l.Exceeding(sc)
+	if len(exceeding) == 0 {
+		return w
+	}
+	prefix := l.KeyPrefix
+	if prefix == "" {
+		prefix = DefaultBaggageKeyPrefix
+	}
+	keys := make(map[string]bool, len(exceeding))
+	for k := range exceeding {
+		keys[strings.ToLower(prefix+k)] = true
+	}
+	return &baggageWriter{
+		base: w,
+		keys: keys,
+	}
+}
+
+// baggageWriter drops writes of given lower cased carrier keys
+type baggageWriter struct {
+	base opentracing.TextMapWriter
+	keys map[string]bool
+}
+
+func (w *baggageWriter) Set(key, val string) {
+	if w.keys[strings.ToLower(key)] {
+		return
+	}
+	w.base.Set(key, val)
+}
+
+// BaggageFromHeaders sets values of request headers as baggage items to span.
+// Headers maps header names to baggage keys, e.g. "X-Tenant-ID": "tenant_id".
+func BaggageFromHeaders(span opentracing.Span, h http.Header, headers map[string]string, limits BaggageLimits) {
+	if span == nil {
+		return
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if value := h.Get(name); value != "" {
+			limits.SetBaggageItem(span, headers[name], value)
+		}
+	}
+}
+
+// PromoteBaggage sets baggage items with given keys as tags to span
+func PromoteBaggage(span opentracing.Span, keys ...string) {
+	if span == nil {
+		return
+	}
+	for _, key := range keys {
+		if value := span.BaggageItem(key); value != "" {
+			span.SetTag(key, value)
+		}
+	}
+}
+
+type baggageTagsKey struct{}
+
+// ContextWithBaggageTags stores baggage keys, which should be promoted
+// as tags to spans started beneath given context
+func ContextWithBaggageTags(ctx context.Context, keys ...string) context.Context {
+	if len(keys) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, baggageTagsKey{}, keys)
+}
+
+// BaggageTagsFromContext returns baggage keys stored to context
+func BaggageTagsFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	keys, _ := ctx.Value(baggageTagsKey{}).([]string)
+	return keys
+}
+
+// PromoteContextBaggage sets baggage items with keys stored to
+// context with ContextWithBaggageTags as tags to span
+func PromoteContextBaggage(ctx context.Context, span opentracing.Span) {
+	PromoteBaggage(span, BaggageTagsFromContext(ctx)...)
+}
+
+// StartSpanFromContext starts span like opentracing.StartSpanFromContext
+// and promotes baggage items with keys stored to context as tags. It is
+// used by integrations, so that baggage tags are set to every span started
+// beneath context.
+func StartSpanFromContext(ctx context.Context, operationName string, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, operationName, opts...)
+	PromoteContextBaggage(ctx, span)
+	return span, ctx
+}
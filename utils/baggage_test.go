@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestBaggageLimitsSetBaggageItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   BaggageLimits
+		existing map[string]string
+		key      string
+		value    string
+		want     bool
+	}{
+		{"no limits", BaggageLimits{}, map[string]string{"a": "1"}, "tenant_id", "42", true},
+		{"empty key", BaggageLimits{}, nil, "", "42", false},
+		{"item too large", BaggageLimits{MaxItemSize: 5}, nil, "tenant_id", "42", false},
+		{"too many items", BaggageLimits{MaxItems: 1}, map[string]string{"a": "1"}, "tenant_id", "42", false},
+		{"replace existing item", BaggageLimits{MaxItems: 1}, map[string]string{"tenant_id": "1"}, "tenant_id", "42", true},
+		{"total size exceeded", BaggageLimits{MaxTotalSize: 12}, map[string]string{"a": "1"}, "tenant_id", "42", false},
+		{"total size fits", BaggageLimits{MaxTotalSize: 13}, map[string]string{"a": "1"}, "tenant_id", "42", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := mocktracer.New().StartSpan("test")
+			for k, v := range tt.existing {
+				span.SetBaggageItem(k, v)
+			}
+			if got := tt.limits.SetBaggageItem(span, tt.key, tt.value); got != tt.want {
+				t.Errorf("SetBaggageItem() = %v, want %v", got, tt.want)
+			}
+			if tt.want && span.BaggageItem(tt.key) != tt.value {
+				t.Errorf("baggage item not set")
+			}
+		})
+	}
+}
+
+func TestBaggageLimitsWriter(t *testing.T) {
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test")
+	span.SetBaggageItem("a", "1")
+	span.SetBaggageItem("b", "2")
+	span.SetBaggageItem("c", "3")
+
+	limits := BaggageLimits{MaxItems: 2, KeyPrefix: "mockpfx-baggage-"}
+	if got := limits.Exceeding(span.Context()); !reflect.DeepEqual(got, map[string]string{"c": "3"}) {
+		t.Errorf("Exceeding() = %v", got)
+	}
+
+	h := http.Header{}
+	tracer.Inject(span.Context(), opentracing.HTTPHeaders,
+		limits.Writer(span.Context(), opentracing.HTTPHeadersCarrier(h)))
+
+	sc, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(h))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]string{}
+	sc.ForeachBaggageItem(func(k, v string) bool {
+		got[k] = v
+		return true
+	})
+	if !reflect.DeepEqual(got, map[string]string{"a": "1", "b": "2"}) {
+		t.Errorf("incorrect baggage injected: %v", got)
+	}
+
+	h = http.Header{}
+	limits.Writer(span.Context(), opentracing.HTTPHeadersCarrier(h)).Set("X-Abc", "3")
+	if h.Get("X-Abc") != "3" {
+		t.Errorf("header, which is not baggage item, dropped")
+	}
+}
+
+func TestBaggageFromHeaders(t *testing.T) {
+	span := mocktracer.New().StartSpan("test")
+	h := http.Header{}
+	h.Set("X-Tenant-ID", "42")
+	h.Set("X-User-Tier", "gold")
+
+	BaggageFromHeaders(span, h, map[string]string{
+		"X-Tenant-ID":  "tenant_id",
+		"X-User-Tier":  "user_tier",
+		"X-Experiment": "experiment",
+	}, BaggageLimits{})
+	PromoteBaggage(span, "tenant_id", "experiment")
+	span.Finish()
+
+	if span.BaggageItem("tenant_id") != "42" || span.BaggageItem("user_tier") != "gold" {
+		t.Errorf("baggage items not set from headers")
+	}
+	tags := span.(*mocktracer.MockSpan).Tags()
+	if !reflect.DeepEqual(tags, map[string]interface{}{"tenant_id": "42"}) {
+		t.Errorf("incorrect promoted tags: %v", tags)
+	}
+}
+
+func TestBaggageTagsFromContext(t *testing.T) {
+	ctx := context.Background()
+	if keys := BaggageTagsFromContext(ctx); keys != nil {
+		t.Errorf("expected no keys, got %v", keys)
+	}
+	ctx = ContextWithBaggageTags(ctx, "tenant_id")
+	if keys := BaggageTagsFromContext(ctx); !reflect.DeepEqual(keys, []string{"tenant_id"}) {
+		t.Errorf("incorrect keys: %v", keys)
+	}
+}
+
+func TestStartSpanFromContext(t *testing.T) {
+	tracer := mocktracer.New()
+	parent := tracer.StartSpan("parent")
+	parent.SetBaggageItem("tenant_id", "42")
+	ctx := opentracing.ContextWithSpan(context.Background(), parent)
+	ctx = ContextWithBaggageTags(ctx, "tenant_id")
+
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	span, ctx := StartSpanFromContext(ctx, "child")
+	if opentracing.SpanFromContext(ctx) != span {
+		t.Errorf("span not in returned context")
+	}
+	if v := span.(*mocktracer.MockSpan).Tag("tenant_id"); v != "42" {
+		t.Errorf("baggage item not promoted to tag: %v", v)
+	}
+}
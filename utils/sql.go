@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SQLObfuscator replaces literals of SQL statements with placeholders,
+// so that statements can be tagged to spans without leaking values
+// and statements differing only by values have same resource name.
+type SQLObfuscator struct {
+	// MaxLength is maximum length of obfuscated statement in runes.
+	// Longer statements are cut and end with "...". Zero value
+	// means that length is not limited.
+	MaxLength int
+	// BackslashEscapes makes backslash escape character in all string
+	// literals like in MySQL. By default backslash escapes only in
+	// E'...' strings like in PostgreSQL.
+	BackslashEscapes bool
+}
+
+// ObfuscateSQL obfuscates statement without length limit
+func ObfuscateSQL(query string) string {
+	return SQLObfuscator{}.Obfuscate(query)
+}
+
+// Obfuscate replaces string and numeric literals with "?", collapses
+// lists of IN clauses to single "?", removes comments and replaces
+// whitespace sequences with single space. Placeholders, quoted
+// identifiers and keywords are kept as they are.
+func (o SQLObfuscator) Obfuscate(query string) string {
+	tokens := collapseINLists(tokenizeSQL(query, o.BackslashEscapes))
+
+	b := &strings.Builder{}
+	for i, t := range tokens {
+		if t.space && i > 0 {
+			b.WriteByte(' ')
+		}
+		if t.kind == sqlLiteral {
+			b.WriteByte('?')
+		} else {
+			b.WriteString(t.text)
+		}
+	}
+	return Truncate(b.String(), o.MaxLength)
+}
+
+// sqlTokenKind is kind of SQL token
+type sqlTokenKind int
+
+const (
+	sqlIdentifier sqlTokenKind = iota
+	sqlLiteral
+	sqlPlaceholder
+	sqlOperator
+)
+
+// sqlToken is token of SQL statement. Space is true, if
+// token is preceded by whitespace or comment.
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	space bool
+}
+
+// tokenizeSQL splits statement to tokens leaving out whitespace and comments.
+// Backslash escapes quotes in string literals, if backslashEscapes is true.
+func tokenizeSQL(query string, backslashEscapes bool) []sqlToken {
+	tokens := []sqlToken{}
+	space := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		var kind sqlTokenKind
+		end := i + 1
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			space = true
+			i++
+			continue
+		case strings.HasPrefix(query[i:], "--"):
+			space = true
+			i = indexFrom(query, i, "\n", 0)
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			space = true
+			i = indexFrom(query, i+2, "*/", 2)
+			continue
+		case c == '\'':
+			kind, end = sqlLiteral, endOfQuoted(query, i, '\'', backslashEscapes)
+		case (c == 'E' || c == 'e') && i+1 < len(query) && query[i+1] == '\'':
+			kind, end = sqlLiteral, endOfQuoted(query, i+1, '\'', true)
+		case (c == 'N' || c == 'n' || c == 'X' || c == 'x') && i+1 < len(query) && query[i+1] == '\'':
+			kind, end = sqlLiteral, endOfQuoted(query, i+1, '\'', backslashEscapes)
+		case c == '"' || c == '`':
+			kind, end = sqlIdentifier, endOfQuoted(query, i, c, false)
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			kind, end = sqlPlaceholder, endOfDigits(query, i+1)
+		case c == '$':
+			if tag, ok := dollarTag(query, i); ok {
+				kind, end = sqlLiteral, indexFrom(query, i+len(tag), tag, len(tag))
+			} else {
+				kind = sqlOperator
+			}
+		case c == '?':
+			kind = sqlPlaceholder
+		case c >= utf8.RuneSelf && isSpace(query, i):
+			space = true
+			_, size := utf8.DecodeRuneInString(query[i:])
+			i += size
+			continue
+		case c == ':' && i+1 < len(query) && isIdentifierStart(query, i+1) && !precededBy(query, i, ':'):
+			kind, end = sqlPlaceholder, endOfIdentifier(query, i+1)
+		case isDigit(c) || (c == '.' && i+1 < len(query) && isDigit(query[i+1])):
+			kind, end = sqlLiteral, endOfNumber(query, i)
+		case c == '-' && i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') && expectsOperand(tokens):
+			kind, end = sqlLiteral, endOfNumber(query, i+1)
+		case isIdentifierStart(query, i):
+			kind, end = sqlIdentifier, endOfIdentifier(query, i)
+		default:
+			kind = sqlOperator
+			if _, size := utf8.DecodeRuneInString(query[i:]); size > 1 {
+				end = i + size
+			}
+		}
+		if end <= i {
+			// every token must advance at least one rune
+			_, size := utf8.DecodeRuneInString(query[i:])
+			end = i + size
+		}
+
+		tokens = append(tokens, sqlToken{kind: kind, text: query[i:end], space: space})
+		space = false
+		i = end
+	}
+	return tokens
+}
+
+// collapseINLists replaces lists of literals and placeholders
+// in IN clauses with single literal
+func collapseINLists(tokens []sqlToken) []sqlToken {
+	out := make([]sqlToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		out = append(out, tokens[i])
+		if tokens[i].kind != sqlIdentifier || !strings.EqualFold(tokens[i].text, "IN") ||
+			i+1 >= len(tokens) || tokens[i+1].text != "(" {
+			continue
+		}
+
+		// list must contain only values separated by commas
+		end := -1
+		for j := i + 2; j < len(tokens); j += 2 {
+			if tokens[j].kind != sqlLiteral && tokens[j].kind != sqlPlaceholder {
+				break
+			}
+			if j+1 < len(tokens) && tokens[j+1].text == ")" {
+				end = j + 1
+				break
+			}
+			if j+1 >= len(tokens) || tokens[j+1].text != "," {
+				break
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		value := tokens[i+2]
+		value.kind = sqlLiteral
+		out = append(out, tokens[i+1], value, tokens[end])
+		i = end
+	}
+	return out
+}
+
+// expectsOperand returns true, if minus sign after tokens
+// is sign of number instead of subtraction operator
+func expectsOperand(tokens []sqlToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	last := tokens[len(tokens)-1]
+	switch last.kind {
+	case sqlOperator:
+		return last.text != ")"
+	case sqlIdentifier:
+		switch strings.ToUpper(last.text) {
+		case "SELECT", "WHERE", "AND", "OR", "NOT", "BY", "SET", "VALUES",
+			"THEN", "ELSE", "WHEN", "LIMIT", "OFFSET", "RETURN", "BETWEEN", "IS":
+			return true
+		}
+	}
+	return false
+}
+
+// indexFrom returns index after first occurrence of substring at or after
+// given position plus skipped length, or end of query if it is not found
+func indexFrom(query string, from int, substr string, skip int) int {
+	if i := strings.Index(query[from:], substr); i >= 0 {
+		return from + i + skip
+	}
+	return len(query)
+}
+
+// endOfQuoted returns index after quoted string starting at given position.
+// Quotes are escaped by doubling them or with backslash, if backslashEscapes
+// is true.
+func endOfQuoted(query string, start int, quote byte, backslashEscapes bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslashEscapes {
+				i++
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// dollarTag returns tag of dollar quoted string such as $$ or $body$
+func dollarTag(query string, start int) (string, bool) {
+	for i := start + 1; i < len(query); i++ {
+		c := query[i]
+		if c == '$' {
+			return query[start : i+1], true
+		}
+		if !isIdentifierPart(c) {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// endOfNumber returns index after numeric literal, which may be
+// hexadecimal, decimal or have exponent
+func endOfNumber(query string, start int) int {
+	i := start
+	if strings.HasPrefix(query[i:], "0x") || strings.HasPrefix(query[i:], "0X") {
+		i += 2
+		for i < len(query) && strings.IndexByte("0123456789abcdefABCDEF", query[i]) >= 0 {
+			i++
+		}
+		return i
+	}
+	i = endOfDigits(query, i)
+	if i < len(query) && query[i] == '.' {
+		i = endOfDigits(query, i+1)
+	}
+	if i < len(query) && (query[i] == 'e' || query[i] == 'E') {
+		j := i + 1
+		if j < len(query) && (query[j] == '+' || query[j] == '-') {
+			j++
+		}
+		if j < len(query) && isDigit(query[j]) {
+			i = endOfDigits(query, j)
+		}
+	}
+	return i
+}
+
+func endOfDigits(query string, start int) int {
+	i := start
+	for i < len(query) && isDigit(query[i]) {
+		i++
+	}
+	return i
+}
+
+func endOfIdentifier(query string, start int) int {
+	i := start
+	for i < len(query) {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func precededBy(query string, i int, c byte) bool {
+	return i > 0 && query[i-1] == c
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentifierStart returns true, if rune at given position is letter or underscore
+func isIdentifierStart(query string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(query[i:])
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isSpace returns true, if rune at given position is whitespace
+func isSpace(query string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(query[i:])
+	return unicode.IsSpace(r)
+}
+
+// isIdentifierPart returns true, if byte can be part of identifier.
+// Bytes of multi-byte runes are accepted, as tags are compared as bytes.
+func isIdentifierPart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c) || c >= utf8.RuneSelf
+}
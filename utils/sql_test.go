@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSQLObfuscatorObfuscate(t *testing.T) {
+	tests := []struct {
+		name       string
+		obfuscator SQLObfuscator
+		query      string
+		want       string
+	}{
+		{"placeholders", SQLObfuscator{}, "SELECT id FROM orders WHERE id = $1 AND state = ?", "SELECT id FROM orders WHERE id = $1 AND state = ?"},
+		{"string literal", SQLObfuscator{}, "SELECT * FROM users WHERE email = 'john.doe@example.com'", "SELECT * FROM users WHERE email = ?"},
+		{"escaped quotes", SQLObfuscator{}, `UPDATE users SET name = 'O''Brien', bio = E'say \'hi\'' WHERE id = 1`, "UPDATE users SET name = ?, bio = ? WHERE id = ?"},
+		{"backslash in standard string", SQLObfuscator{}, `SELECT * FROM f WHERE path = 'C:\' AND token = 'secret-token'`, "SELECT * FROM f WHERE path = ? AND token = ?"},
+		{"backslash escapes", SQLObfuscator{BackslashEscapes: true}, `UPDATE users SET bio = 'say \'hi\'', path = 'C:\\' WHERE id = 1`, "UPDATE users SET bio = ?, path = ? WHERE id = ?"},
+		{"prefixed strings", SQLObfuscator{}, "SELECT E'line\\n', N'name', X'ff'", "SELECT ?, ?, ?"},
+		{"dollar quoted", SQLObfuscator{}, "SELECT $body$it's secret$body$, $$x$$", "SELECT ?, ?"},
+		{"numbers", SQLObfuscator{}, "SELECT * FROM items WHERE price > 10.5 AND qty < 1e3 AND flags = 0xFF AND ratio = .5", "SELECT * FROM items WHERE price > ? AND qty < ? AND flags = ? AND ratio = ?"},
+		{"negative numbers", SQLObfuscator{}, "SELECT a-1, b - 2 FROM t WHERE c = -3 AND d IN (-4, 5)", "SELECT a-?, b - ? FROM t WHERE c = ? AND d IN (?)"},
+		{"identifiers with digits", SQLObfuscator{}, "SELECT col1, t2.x FROM table2 AS t2", "SELECT col1, t2.x FROM table2 AS t2"},
+		{"quoted identifiers", SQLObfuscator{}, "SELECT \"user id\", `order` FROM \"Users\"", "SELECT \"user id\", `order` FROM \"Users\""},
+		{"in list", SQLObfuscator{}, "SELECT * FROM t WHERE id IN (1, 2, 3) AND name in ('a','b')", "SELECT * FROM t WHERE id IN (?) AND name in (?)"},
+		{"in list of placeholders", SQLObfuscator{}, "SELECT * FROM t WHERE id IN ($1, $2, $3)", "SELECT * FROM t WHERE id IN (?)"},
+		{"in subquery", SQLObfuscator{}, "SELECT * FROM t WHERE id IN (SELECT id FROM u WHERE x = 1)", "SELECT * FROM t WHERE id IN (SELECT id FROM u WHERE x = ?)"},
+		{"whitespace and comments", SQLObfuscator{}, "SELECT id -- primary key\n\tFROM /* all */ orders\n\n", "SELECT id FROM orders"},
+		{"casts and named parameters", SQLObfuscator{}, "SELECT :id::text, created_at::date", "SELECT :id::text, created_at::date"},
+		{"unicode", SQLObfuscator{}, "SELECT * FROM käyttäjät WHERE nimi = 'Äijä'", "SELECT * FROM käyttäjät WHERE nimi = ?"},
+		{"non-letter unicode operator", SQLObfuscator{}, "SELECT a ≥ b — c", "SELECT a ≥ b — c"},
+		{"non-breaking space", SQLObfuscator{}, "SELECT a\u00a0FROM t WHERE x = 'y'", "SELECT a FROM t WHERE x = ?"},
+		{"invalid UTF-8", SQLObfuscator{}, "SELECT \xff FROM t", "SELECT \xff FROM t"},
+		{"unterminated string", SQLObfuscator{}, "SELECT 'secret", "SELECT ?"},
+		{"max length", SQLObfuscator{MaxLength: 20}, "SELECT id, name, email FROM users", "SELECT id, name, ..."},
+		{"short max length", SQLObfuscator{MaxLength: 2}, "SELECT", "SE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.obfuscator.Obfuscate(tt.query); got != tt.want {
+				t.Errorf("Obfuscate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleObfuscateSQL() {
+	fmt.Println(ObfuscateSQL("SELECT * FROM orders\n  WHERE customer = 'jane' AND id IN (1, 2, 3)"))
+	// Output: SELECT * FROM orders WHERE customer = ? AND id IN (?)
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"unicode/utf8"
+)
+
+// Truncate cuts string to given number of runes ending with "...".
+// String is not cut, if max is not positive.
+func Truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	const ellipsis = "..."
+	if max <= len(ellipsis) {
+		return string([]rune(s)[:max])
+	}
+	return string([]rune(s)[:max-len(ellipsis)]) + ellipsis
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"GET key", 0, "GET key"},
+		{"GET key", 7, "GET key"},
+		{"GET key", 6, "GET..."},
+		{"äöå", 2, "äö"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.max); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}